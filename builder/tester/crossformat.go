@@ -0,0 +1,173 @@
+package main
+
+import (
+	"debug/macho"
+	"debug/pe"
+	"fmt"
+	"os"
+)
+
+// ExecutableType values for non-ELF binary formats, so containers that
+// embed macOS/Windows helper executables (or multi-arch payloads) get
+// useful results instead of an opaque error.
+const (
+	ExecutableTypeMachOBinary ExecutableType = "macho-binary"
+	ExecutableTypePEBinary    ExecutableType = "pe-binary"
+	ExecutableTypeFatBinary   ExecutableType = "fat-binary"
+)
+
+// binaryMagic sniffs the first bytes of a file to decide which debug/*
+// package should parse it.
+type binaryMagic int
+
+const (
+	magicUnknown binaryMagic = iota
+	magicELF
+	magicMachO
+	magicFatMachO
+	magicPE
+)
+
+func sniffBinaryMagic(full string) (binaryMagic, error) {
+	f, err := os.Open(full)
+	if err != nil {
+		return magicUnknown, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 4)
+	n, err := f.Read(buf)
+	if err != nil || n < 4 {
+		return magicUnknown, nil
+	}
+
+	switch {
+	case buf[0] == 0x7f && buf[1] == 'E' && buf[2] == 'L' && buf[3] == 'F':
+		return magicELF, nil
+	case isFatMachOMagic(buf):
+		return magicFatMachO, nil
+	case isMachOMagic(buf):
+		return magicMachO, nil
+	case buf[0] == 'M' && buf[1] == 'Z':
+		return magicPE, nil
+	default:
+		return magicUnknown, nil
+	}
+}
+
+func isMachOMagic(buf []byte) bool {
+	be := uint32(buf[0])<<24 | uint32(buf[1])<<16 | uint32(buf[2])<<8 | uint32(buf[3])
+	le := uint32(buf[3])<<24 | uint32(buf[2])<<16 | uint32(buf[1])<<8 | uint32(buf[0])
+	switch be {
+	case macho.Magic32, macho.Magic64, macho.MagicFat:
+		return true
+	}
+	switch le {
+	case macho.Magic32, macho.Magic64:
+		return true
+	}
+	return false
+}
+
+func isFatMachOMagic(buf []byte) bool {
+	be := uint32(buf[0])<<24 | uint32(buf[1])<<16 | uint32(buf[2])<<8 | uint32(buf[3])
+	le := uint32(buf[3])<<24 | uint32(buf[2])<<16 | uint32(buf[1])<<8 | uint32(buf[0])
+	return be == macho.MagicFat || le == macho.MagicFat
+}
+
+// analyzeMachO extracts LC_LOAD_DYLIB and LC_RPATH entries from a single-arch
+// Mach-O binary.
+func analyzeMachO(full string) (ExecutableResult, error) {
+	var ret ExecutableResult
+	ret.ExecutableType = ExecutableTypeMachOBinary
+
+	f, err := macho.Open(full)
+	if err != nil {
+		return ret, fmt.Errorf("opening %q as Mach-O: %w", full, err)
+	}
+	defer f.Close()
+
+	libs, err := f.ImportedLibraries()
+	if err != nil {
+		return ret, fmt.Errorf("reading LC_LOAD_DYLIB for %q: %w", full, err)
+	}
+	ret.Needed = libs
+	for _, lib := range libs {
+		ret.Dependencies = append(ret.Dependencies, ExecutableResult{FullPath: lib})
+	}
+
+	for _, load := range f.Loads {
+		if rpath, ok := load.(*macho.Rpath); ok {
+			if ret.RPath != "" {
+				ret.RPath += ":"
+			}
+			ret.RPath += rpath.Path
+		}
+	}
+
+	return ret, nil
+}
+
+// analyzeFatMachO enumerates every architecture embedded in a fat (universal)
+// Mach-O binary, analyzing each as a dependency of the top-level result.
+func analyzeFatMachO(full string) (ExecutableResult, error) {
+	var ret ExecutableResult
+	ret.ExecutableType = ExecutableTypeFatBinary
+
+	fat, err := macho.OpenFat(full)
+	if err != nil {
+		return ret, fmt.Errorf("opening %q as fat Mach-O: %w", full, err)
+	}
+	defer fat.Close()
+
+	for _, arch := range fat.Arches {
+		dep := ExecutableResult{
+			FullPath:       fmt.Sprintf("%s (%s)", full, arch.Cpu),
+			ExecutableType: ExecutableTypeMachOBinary,
+		}
+		libs, err := arch.ImportedLibraries()
+		if err != nil {
+			dep.Error = err.Error()
+		} else {
+			dep.Needed = libs
+			for _, lib := range libs {
+				dep.Dependencies = append(dep.Dependencies, ExecutableResult{FullPath: lib})
+			}
+			for _, load := range arch.Loads {
+				if rpath, ok := load.(*macho.Rpath); ok {
+					if dep.RPath != "" {
+						dep.RPath += ":"
+					}
+					dep.RPath += rpath.Path
+				}
+			}
+		}
+		ret.Dependencies = append(ret.Dependencies, dep)
+	}
+
+	return ret, nil
+}
+
+// analyzePE enumerates the import directory's DLL names for a Windows PE
+// binary.
+func analyzePE(full string) (ExecutableResult, error) {
+	var ret ExecutableResult
+	ret.ExecutableType = ExecutableTypePEBinary
+
+	f, err := pe.Open(full)
+	if err != nil {
+		return ret, fmt.Errorf("opening %q as PE: %w", full, err)
+	}
+	defer f.Close()
+
+	libs, err := f.ImportedLibraries()
+	if err != nil {
+		return ret, fmt.Errorf("reading import directory for %q: %w", full, err)
+	}
+	ret.Needed = libs
+	for _, lib := range libs {
+		ret.Dependencies = append(ret.Dependencies, ExecutableResult{FullPath: lib})
+	}
+
+	return ret, nil
+}
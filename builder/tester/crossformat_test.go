@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTempFile(t *testing.T, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bin")
+	if err := os.WriteFile(path, data, 0o755); err != nil {
+		t.Fatalf("write temp file: %v", err)
+	}
+	return path
+}
+
+func TestSniffBinaryMagic(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+		want binaryMagic
+	}{
+		{
+			name: "ELF",
+			data: []byte{0x7f, 'E', 'L', 'F', 0, 0, 0, 0},
+			want: magicELF,
+		},
+		{
+			name: "Mach-O 32-bit little-endian",
+			data: []byte{0xce, 0xfa, 0xed, 0xfe, 0, 0, 0, 0},
+			want: magicMachO,
+		},
+		{
+			name: "Mach-O 64-bit little-endian",
+			data: []byte{0xcf, 0xfa, 0xed, 0xfe, 0, 0, 0, 0},
+			want: magicMachO,
+		},
+		{
+			name: "fat Mach-O",
+			data: []byte{0xca, 0xfe, 0xba, 0xbe, 0, 0, 0, 1},
+			want: magicFatMachO,
+		},
+		{
+			name: "PE (MZ)",
+			data: []byte{'M', 'Z', 0, 0, 0, 0, 0, 0},
+			want: magicPE,
+		},
+		{
+			name: "unrecognized",
+			data: []byte{'#', '!', '/', 'b', 'i', 'n', '/', 's'},
+			want: magicUnknown,
+		},
+		{
+			name: "too short",
+			data: []byte{0x7f},
+			want: magicUnknown,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeTempFile(t, tc.data)
+			got, err := sniffBinaryMagic(path)
+			if err != nil {
+				t.Fatalf("sniffBinaryMagic: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("sniffBinaryMagic = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// buildMinimalMachO assembles the smallest Mach-O 32-bit header debug/macho
+// will accept: just the FileHeader, with no load commands (Ncmd/Cmdsz both
+// zero), so ImportedLibraries comes back empty rather than erroring.
+func buildMinimalMachO() []byte {
+	buf := make([]byte, 28) // 7 uint32 fields
+	binary.LittleEndian.PutUint32(buf[0:4], 0xfeedface)  // Magic32
+	binary.LittleEndian.PutUint32(buf[4:8], 7)            // Cpu (CPU_TYPE_X86)
+	binary.LittleEndian.PutUint32(buf[8:12], 3)           // SubCpu
+	binary.LittleEndian.PutUint32(buf[12:16], 2)          // Type (TypeExec)
+	binary.LittleEndian.PutUint32(buf[16:20], 0)          // Ncmd
+	binary.LittleEndian.PutUint32(buf[20:24], 0)          // Cmdsz
+	binary.LittleEndian.PutUint32(buf[24:28], 0)          // Flags
+	return buf
+}
+
+func TestAnalyzeMachO(t *testing.T) {
+	path := writeTempFile(t, buildMinimalMachO())
+
+	res, err := analyzeMachO(path)
+	if err != nil {
+		t.Fatalf("analyzeMachO: %v", err)
+	}
+	if res.ExecutableType != ExecutableTypeMachOBinary {
+		t.Errorf("ExecutableType = %q, want %q", res.ExecutableType, ExecutableTypeMachOBinary)
+	}
+	if len(res.Needed) != 0 {
+		t.Errorf("Needed = %v, want empty for a binary with no load commands", res.Needed)
+	}
+}
+
+// buildMinimalPE assembles the smallest PE file debug/pe will accept: a DOS
+// header pointing at a "PE\0\0" signature followed by a bare FileHeader with
+// no sections, symbol table, or optional header.
+func buildMinimalPE() []byte {
+	dos := make([]byte, 96)
+	dos[0], dos[1] = 'M', 'Z'
+	binary.LittleEndian.PutUint32(dos[0x3c:], 96) // e_lfanew points right after the DOS header
+
+	sig := []byte("PE\x00\x00")
+
+	fileHeader := make([]byte, 20)
+	binary.LittleEndian.PutUint16(fileHeader[0:2], 0x8664) // IMAGE_FILE_MACHINE_AMD64
+	binary.LittleEndian.PutUint16(fileHeader[2:4], 0)       // NumberOfSections
+	binary.LittleEndian.PutUint32(fileHeader[4:8], 0)       // TimeDateStamp
+	binary.LittleEndian.PutUint32(fileHeader[8:12], 0)      // PointerToSymbolTable
+	binary.LittleEndian.PutUint32(fileHeader[12:16], 0)     // NumberOfSymbols
+	binary.LittleEndian.PutUint16(fileHeader[16:18], 0)     // SizeOfOptionalHeader
+	binary.LittleEndian.PutUint16(fileHeader[18:20], 0)     // Characteristics
+
+	var buf []byte
+	buf = append(buf, dos...)
+	buf = append(buf, sig...)
+	buf = append(buf, fileHeader...)
+	return buf
+}
+
+func TestAnalyzePE(t *testing.T) {
+	path := writeTempFile(t, buildMinimalPE())
+
+	res, err := analyzePE(path)
+	if err != nil {
+		t.Fatalf("analyzePE: %v", err)
+	}
+	if res.ExecutableType != ExecutableTypePEBinary {
+		t.Errorf("ExecutableType = %q, want %q", res.ExecutableType, ExecutableTypePEBinary)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"debug/elf"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExecutableTypePIEBinary refines ExecutableTypeDynamicBinary with the extra
+// detail debug/elf can recover without shelling out to ldd.
+const (
+	ExecutableTypePIEBinary ExecutableType = "pie-binary"
+)
+
+// analyzeELF replaces shelling out to ldd with in-process analysis of the
+// ELF program/section headers: PT_INTERP names the dynamic linker, the
+// .dynamic section's DT_NEEDED entries enumerate required SONAMEs, and
+// DT_RPATH/DT_RUNPATH give the library search path to resolve them against.
+func (ct *containerTester) analyzeELF(full string, f *elf.File) (ExecutableResult, error) {
+	var ret ExecutableResult
+
+	interp, hasInterp, err := elfInterpreter(f)
+	if err != nil {
+		return ret, fmt.Errorf("reading PT_INTERP for %q: %w", full, err)
+	}
+
+	if !hasInterp {
+		ret.ExecutableType = ExecutableTypeStaticBinary
+		return ret, nil
+	}
+
+	ret.Interpreter = interp
+	ret.Dependencies = append(ret.Dependencies, ExecutableResult{FullPath: interp})
+
+	needed, rpath, runpath, err := elfDynamicEntries(f)
+	if err != nil {
+		return ret, fmt.Errorf("reading .dynamic for %q: %w", full, err)
+	}
+	ret.Needed = needed
+	ret.RPath = rpath
+	ret.RunPath = runpath
+
+	isPIE := f.Type == elf.ET_DYN && elfHasPIEFlag(f)
+	if isPIE {
+		ret.ExecutableType = ExecutableTypePIEBinary
+	} else {
+		ret.ExecutableType = ExecutableTypeDynamicBinary
+	}
+
+	searchPath := buildLibrarySearchPath(full, rpath, runpath)
+	ret.SearchPath = searchPath
+
+	for _, soname := range needed {
+		dep := ExecutableResult{FullPath: soname}
+		if resolved, ok := resolveSharedLibrary(soname, searchPath); ok {
+			dep.FullPath = resolved
+		} else {
+			dep.Error = fmt.Sprintf("dependency not found: %s", soname)
+		}
+		ret.Dependencies = append(ret.Dependencies, dep)
+	}
+
+	return ret, nil
+}
+
+// elfInterpreter reads PT_INTERP, returning ("", false, nil) for a
+// statically linked binary (no such program header).
+func elfInterpreter(f *elf.File) (string, bool, error) {
+	for _, prog := range f.Progs {
+		if prog.Type != elf.PT_INTERP {
+			continue
+		}
+		data := make([]byte, prog.Filesz)
+		if _, err := prog.ReadAt(data, 0); err != nil {
+			return "", true, err
+		}
+		return strings.TrimRight(string(data), "\x00"), true, nil
+	}
+	return "", false, nil
+}
+
+// elfDynamicEntries walks the SHT_DYNAMIC section for DT_NEEDED, DT_RPATH,
+// and DT_RUNPATH entries.
+func elfDynamicEntries(f *elf.File) (needed []string, rpath string, runpath string, err error) {
+	libs, err := f.ImportedLibraries()
+	if err != nil {
+		return nil, "", "", err
+	}
+	needed = libs
+
+	if rp, rerr := f.DynString(elf.DT_RPATH); rerr == nil && len(rp) > 0 {
+		rpath = rp[0]
+	}
+	if rp, rerr := f.DynString(elf.DT_RUNPATH); rerr == nil && len(rp) > 0 {
+		runpath = rp[0]
+	}
+
+	return needed, rpath, runpath, nil
+}
+
+// elfHasPIEFlag reports whether an ET_DYN binary is a real PIE executable
+// (DF_1_PIE set in DT_FLAGS_1) rather than a plain shared library.
+func elfHasPIEFlag(f *elf.File) bool {
+	flags, err := f.DynValue(elf.DT_FLAGS_1)
+	if err != nil || len(flags) == 0 {
+		return false
+	}
+	const dfPIE = 0x08000000 // DF_1_PIE, not exposed as a named constant by debug/elf
+	return flags[0]&dfPIE != 0
+}
+
+// buildLibrarySearchPath assembles the ordered list of directories used to
+// resolve DT_NEEDED SONAMEs, mirroring the ELF loader's own search order:
+// RPATH (legacy, searched before LD_LIBRARY_PATH), LD_LIBRARY_PATH, RUNPATH,
+// then the system default paths from /etc/ld.so.conf.d.
+func buildLibrarySearchPath(binaryPath, rpath, runpath string) []string {
+	origin := filepath.Dir(binaryPath)
+	expand := func(path string) []string {
+		var dirs []string
+		for _, p := range strings.Split(path, ":") {
+			if p == "" {
+				continue
+			}
+			p = strings.ReplaceAll(p, "$ORIGIN", origin)
+			p = strings.ReplaceAll(p, "${ORIGIN}", origin)
+			dirs = append(dirs, p)
+		}
+		return dirs
+	}
+
+	var search []string
+	search = append(search, expand(rpath)...)
+	search = append(search, expand(os.Getenv("LD_LIBRARY_PATH"))...)
+	search = append(search, expand(runpath)...)
+	search = append(search, readLdSoConf()...)
+	search = append(search, "/lib", "/usr/lib", "/lib64", "/usr/lib64")
+	return search
+}
+
+// readLdSoConf parses the default library directories out of
+// /etc/ld.so.conf.d/*.conf, best-effort (missing files are simply skipped).
+func readLdSoConf() []string {
+	matches, _ := filepath.Glob("/etc/ld.so.conf.d/*.conf")
+	var dirs []string
+	for _, path := range matches {
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "include ") {
+				continue
+			}
+			dirs = append(dirs, line)
+		}
+		f.Close()
+	}
+	return dirs
+}
+
+// resolveSharedLibrary walks searchPath looking for soname, returning the
+// resolved full path on success.
+func resolveSharedLibrary(soname string, searchPath []string) (string, bool) {
+	for _, dir := range searchPath {
+		candidate := filepath.Join(dir, soname)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
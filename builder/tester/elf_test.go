@@ -0,0 +1,148 @@
+package main
+
+import (
+	"debug/elf"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// compileC compiles a trivial C program with the given extra cc flags and
+// returns the path to the resulting binary. It skips the test if cc isn't
+// available, so this runs wherever a build toolchain is present (as it is
+// in the container images this tool analyzes) and is skipped elsewhere.
+func compileC(t *testing.T, extraArgs ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("cc"); err != nil {
+		t.Skip("cc not available")
+	}
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "main.c")
+	if err := os.WriteFile(src, []byte("int main(void) { return 0; }\n"), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	out := filepath.Join(dir, "bin")
+	args := append([]string{src, "-o", out}, extraArgs...)
+	cmd := exec.Command("cc", args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("cc failed (%v): %s", err, output)
+	}
+	return out
+}
+
+func TestAnalyzeELFStaticBinary(t *testing.T) {
+	bin := compileC(t, "-static")
+
+	f, err := elf.Open(bin)
+	if err != nil {
+		t.Fatalf("elf.Open: %v", err)
+	}
+	defer f.Close()
+
+	ct := &containerTester{}
+	res, err := ct.analyzeELF(bin, f)
+	if err != nil {
+		t.Fatalf("analyzeELF: %v", err)
+	}
+	if res.ExecutableType != ExecutableTypeStaticBinary {
+		t.Errorf("ExecutableType = %q, want %q", res.ExecutableType, ExecutableTypeStaticBinary)
+	}
+	if res.Interpreter != "" {
+		t.Errorf("Interpreter = %q, want empty for a static binary", res.Interpreter)
+	}
+	if len(res.Needed) != 0 {
+		t.Errorf("Needed = %v, want empty for a static binary", res.Needed)
+	}
+}
+
+func TestAnalyzeELFDynamicBinary(t *testing.T) {
+	bin := compileC(t, "-no-pie")
+
+	f, err := elf.Open(bin)
+	if err != nil {
+		t.Fatalf("elf.Open: %v", err)
+	}
+	defer f.Close()
+
+	ct := &containerTester{}
+	res, err := ct.analyzeELF(bin, f)
+	if err != nil {
+		t.Fatalf("analyzeELF: %v", err)
+	}
+	if res.ExecutableType != ExecutableTypeDynamicBinary {
+		t.Errorf("ExecutableType = %q, want %q", res.ExecutableType, ExecutableTypeDynamicBinary)
+	}
+	if res.Interpreter == "" {
+		t.Error("Interpreter is empty, want the dynamic linker path")
+	}
+	if len(res.Needed) == 0 {
+		t.Error("Needed is empty, want at least libc")
+	}
+	if len(res.SearchPath) == 0 {
+		t.Error("SearchPath is empty")
+	}
+	// The dynamic linker itself should show up as the first dependency.
+	if len(res.Dependencies) == 0 || res.Dependencies[0].FullPath != res.Interpreter {
+		t.Errorf("Dependencies[0] = %+v, want the interpreter %q", res.Dependencies, res.Interpreter)
+	}
+}
+
+func TestAnalyzeELFPIEBinary(t *testing.T) {
+	bin := compileC(t, "-pie", "-fPIE")
+
+	f, err := elf.Open(bin)
+	if err != nil {
+		t.Fatalf("elf.Open: %v", err)
+	}
+	defer f.Close()
+
+	ct := &containerTester{}
+	res, err := ct.analyzeELF(bin, f)
+	if err != nil {
+		t.Fatalf("analyzeELF: %v", err)
+	}
+	if res.ExecutableType != ExecutableTypePIEBinary {
+		t.Errorf("ExecutableType = %q, want %q", res.ExecutableType, ExecutableTypePIEBinary)
+	}
+}
+
+func TestBuildLibrarySearchPath(t *testing.T) {
+	t.Setenv("LD_LIBRARY_PATH", "/opt/lib")
+
+	search := buildLibrarySearchPath("/containers/bin/tool", "/containers/lib:$ORIGIN/../lib", "/containers/runlib")
+
+	want := []string{
+		"/containers/lib", "/containers/bin/../lib", // from rpath, $ORIGIN expanded
+		"/opt/lib",          // from LD_LIBRARY_PATH
+		"/containers/runlib", // from runpath
+	}
+	for i, w := range want {
+		if i >= len(search) || search[i] != w {
+			t.Fatalf("search[%d] = %v, want %q (full: %v)", i, search, w, search)
+		}
+	}
+	// The system default directories should still be present, appended last.
+	last := search[len(search)-1]
+	if last != "/usr/lib64" {
+		t.Errorf("last search dir = %q, want /usr/lib64", last)
+	}
+}
+
+func TestResolveSharedLibrary(t *testing.T) {
+	dir := t.TempDir()
+	libPath := filepath.Join(dir, "libfoo.so")
+	if err := os.WriteFile(libPath, []byte("not a real library"), 0o644); err != nil {
+		t.Fatalf("write fake library: %v", err)
+	}
+
+	resolved, ok := resolveSharedLibrary("libfoo.so", []string{"/nonexistent", dir})
+	if !ok || resolved != libPath {
+		t.Errorf("resolveSharedLibrary = (%q, %v), want (%q, true)", resolved, ok, libPath)
+	}
+
+	if _, ok := resolveSharedLibrary("libbar.so", []string{"/nonexistent", dir}); ok {
+		t.Error("resolveSharedLibrary found a library that doesn't exist")
+	}
+}
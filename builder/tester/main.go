@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"context"
+	"debug/elf"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -12,7 +13,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -34,8 +38,25 @@ type ExecutableResult struct {
 
 	Dependencies []ExecutableResult `json:",omitempty"`
 
+	// Interpreter, Needed, RPath, RunPath, and SearchPath are populated by
+	// the debug/elf-based analysis in elf.go for dynamic-binary/pie-binary
+	// executables.
+	Interpreter string   `json:",omitempty"`
+	Needed      []string `json:",omitempty"`
+	RPath       string   `json:",omitempty"`
+	RunPath     string   `json:",omitempty"`
+	SearchPath  []string `json:",omitempty"`
+
 	// Only added if captureOutput is true
 	Output string `json:",omitempty"`
+
+	// TestCase holds the pass/fail result of running this executable's
+	// acceptance test case from -test-spec, if one was defined for it.
+	TestCase *TestCaseResult `json:",omitempty"`
+
+	// Runtime is populated when this executable (or the terminal
+	// interpreter of a shebang chain) is a known language runtime.
+	Runtime *RuntimeInfo `json:",omitempty"`
 }
 
 type TestResults struct {
@@ -46,7 +67,34 @@ type TestResults struct {
 }
 
 type containerTester struct {
-	captureOutput bool
+	captureOutput   bool
+	useLdd          bool
+	parallel        int
+	keepGoing       bool
+	testSpec        TestSpec
+	maxShebangDepth int
+
+	execMu    sync.Mutex
+	execLocks map[string]*sync.Mutex
+}
+
+// execLockFor returns a mutex scoped to full, the resolved path of an
+// executable. Worker goroutines hold it for the duration of captureOutput
+// execution so the same script never runs twice concurrently, even if it
+// appears under multiple deploy-bins/deploy-paths entries.
+func (ct *containerTester) execLockFor(full string) *sync.Mutex {
+	ct.execMu.Lock()
+	defer ct.execMu.Unlock()
+
+	if ct.execLocks == nil {
+		ct.execLocks = make(map[string]*sync.Mutex)
+	}
+	mu, ok := ct.execLocks[full]
+	if !ok {
+		mu = &sync.Mutex{}
+		ct.execLocks[full] = mu
+	}
+	return mu
 }
 
 func (ct *containerTester) isScript(fullPath string) (bool, error) {
@@ -70,7 +118,21 @@ func (ct *containerTester) isScript(fullPath string) (bool, error) {
 	return buf[0] == '#' && buf[1] == '!', nil
 }
 
+// defaultMaxShebangDepth bounds how many script -> interpreter hops
+// testExecutable will follow before giving up, independent of the cycle
+// check (a long chain of distinct wrapper scripts is not a cycle but is
+// still a sign something is wrong).
+const defaultMaxShebangDepth = 10
+
 func (ct *containerTester) testExecutable(name string, top bool) (ExecutableResult, error) {
+	return ct.resolveExecutable(name, top, make(map[string]struct{}))
+}
+
+// resolveExecutable is testExecutable's recursive core. visited carries the
+// resolved full paths seen so far in this call's shebang chain so that a
+// wrapper script that (directly or transitively) shebangs back to itself is
+// reported as a cycle instead of recursing forever.
+func (ct *containerTester) resolveExecutable(name string, top bool, visited map[string]struct{}) (ExecutableResult, error) {
 	var ret ExecutableResult
 
 	// Look up the full path of the executable
@@ -80,11 +142,23 @@ func (ct *containerTester) testExecutable(name string, top bool) (ExecutableResu
 	}
 	ret.FullPath = full
 
+	if _, seen := visited[full]; seen {
+		return ret, fmt.Errorf("cycle detected in shebang chain at %q", full)
+	}
+	maxDepth := ct.maxShebangDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultMaxShebangDepth
+	}
+	if len(visited) >= maxDepth {
+		return ret, fmt.Errorf("shebang chain exceeds max depth %d at %q", maxDepth, full)
+	}
+	visited[full] = struct{}{}
+
 	// Determine if the executable is a script or binary
 	if isScript, err := ct.isScript(full); err != nil {
 		return ret, fmt.Errorf("checking if executable %q is a script: %w", full, err)
 	} else if isScript {
-		// It's a script. Determine the interpreter from the shebang line and use testExecutable.
+		// It's a script. Determine the interpreter from the shebang line and use resolveExecutable.
 		ret.ExecutableType = ExecutableTypeScript
 
 		f, err := os.Open(full)
@@ -115,7 +189,7 @@ func (ct *containerTester) testExecutable(name string, top bool) (ExecutableResu
 			interpreterArgs = strings.TrimSpace(shebang[idx+1:])
 		}
 
-		dep, err := ct.testExecutable(interpreter, false)
+		dep, err := ct.resolveExecutable(interpreter, false, visited)
 		if err != nil {
 			dep.Error = err.Error()
 		}
@@ -140,7 +214,7 @@ func (ct *containerTester) testExecutable(name string, top bool) (ExecutableResu
 					continue
 				}
 
-				dep, err := ct.testExecutable(arg, false)
+				dep, err := ct.resolveExecutable(arg, false, visited)
 				if err != nil {
 					dep.Error = err.Error()
 				}
@@ -148,76 +222,77 @@ func (ct *containerTester) testExecutable(name string, top bool) (ExecutableResu
 				break
 			}
 		}
-	} else {
-		// Assume an ELF binary. Use ldd to find dependencies and handle the case it's a static executable.
-		cmd := exec.Command("ldd", full)
-		output, lddErr := cmd.CombinedOutput()
-		lddOut := string(output)
-		if strings.Contains(lddOut, "statically linked") || strings.Contains(lddOut, "not a dynamic executable") {
-			// Static binary - no shared library dependencies to record.
-			lddErr = nil
-			ret.ExecutableType = ExecutableTypeStaticBinary
-		} else {
-			ret.ExecutableType = ExecutableTypeDynamicBinary
+	} else if ct.useLdd {
+		res, err := ct.legacyLddAnalysis(full)
+		if err != nil {
+			return ret, err
 		}
-		if lddErr != nil && len(lddOut) == 0 {
-			return ret, fmt.Errorf("running ldd on %q: %w", full, lddErr)
+		ret = res
+	} else {
+		magic, err := sniffBinaryMagic(full)
+		if err != nil {
+			return ret, fmt.Errorf("sniffing binary format for %q: %w", full, err)
 		}
 
-		scanner := bufio.NewScanner(strings.NewReader(lddOut))
-		for scanner.Scan() {
-			line := strings.TrimSpace(scanner.Text())
-			if line == "" {
-				continue
+		switch magic {
+		case magicELF:
+			elfFile, err := elf.Open(full)
+			if err != nil {
+				return ret, fmt.Errorf("opening %q as ELF: %w", full, err)
 			}
-			if strings.HasPrefix(line, "ldd:") {
-				// Warning/error from ldd itself; surface as a dependency error for visibility.
-				ret.Dependencies = append(ret.Dependencies, ExecutableResult{Error: line})
-				continue
+			defer elfFile.Close()
+
+			res, err := ct.analyzeELF(full, elfFile)
+			if err != nil {
+				return ret, err
 			}
+			ret = res
+		case magicMachO:
+			res, err := analyzeMachO(full)
+			if err != nil {
+				return ret, err
+			}
+			ret = res
+		case magicFatMachO:
+			res, err := analyzeFatMachO(full)
+			if err != nil {
+				return ret, err
+			}
+			ret = res
+		case magicPE:
+			res, err := analyzePE(full)
+			if err != nil {
+				return ret, err
+			}
+			ret = res
+		default:
+			return ret, fmt.Errorf("unrecognized binary format for %q", full)
+		}
 
-			var dep ExecutableResult
-
-			if strings.Contains(line, "=>") {
-				parts := strings.SplitN(line, "=>", 2)
-				left := strings.TrimSpace(parts[0])
-				right := strings.TrimSpace(parts[1])
-				if strings.Contains(right, "not found") {
-					dep.FullPath = left
-					dep.Error = fmt.Sprintf("dependency missing: %s", line)
-				} else {
-					fields := strings.Fields(right)
-					if len(fields) > 0 && strings.HasPrefix(fields[0], "/") {
-						dep.FullPath = fields[0]
-						if _, err := os.Stat(fields[0]); err != nil {
-							dep.Error = fmt.Sprintf("stat %q: %v", fields[0], err)
-						}
-					}
-				}
+		// The terminal interpreter in a shebang chain (or a runtime binary
+		// tested directly) may resolve fine via exec.LookPath while its
+		// stdlib/site-packages are missing, a common failure mode in
+		// layered container builds. Surface version and module search path
+		// for the runtimes we know how to introspect.
+		if rt := knownRuntimeFor(full); rt != "" {
+			info, err := probeRuntime(full, rt)
+			if err != nil {
+				ret.Error = err.Error()
 			} else {
-				fields := strings.Fields(line)
-				if len(fields) > 0 && strings.HasPrefix(fields[0], "/") {
-					dep.FullPath = fields[0]
-					if _, err := os.Stat(fields[0]); err != nil {
-						dep.Error = fmt.Sprintf("stat %q: %v", fields[0], err)
-					}
-				} else {
-					continue
-				}
+				ret.Runtime = info
 			}
-
-			ret.Dependencies = append(ret.Dependencies, dep)
-		}
-		if err := scanner.Err(); err != nil {
-			return ret, fmt.Errorf("parsing ldd output for %q: %w", full, err)
-		}
-		if lddErr != nil {
-			return ret, fmt.Errorf("running ldd on %q: %w", full, lddErr)
 		}
 	}
 
-	// If captureOutput is true, run the executable and capture its output
+	// If captureOutput is true, run the executable and capture its output.
+	// Serialize execution per full path so parallel workers never run the
+	// same binary/script twice at once; the timeout below stays scoped to
+	// this one job regardless of how long it waits on the lock.
 	if ct.captureOutput && top {
+		mu := ct.execLockFor(full)
+		mu.Lock()
+		defer mu.Unlock()
+
 		// Use a 5 second timeout to avoid hanging indefinitely
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
@@ -233,18 +308,111 @@ func (ct *containerTester) testExecutable(name string, top bool) (ExecutableResu
 	return ret, nil
 }
 
+// legacyLddAnalysis reimplements the original ldd-shelling-out dependency
+// resolution, kept behind -use-ldd for comparison against the debug/elf
+// based analysis in elf.go.
+func (ct *containerTester) legacyLddAnalysis(full string) (ExecutableResult, error) {
+	var ret ExecutableResult
+
+	cmd := exec.Command("ldd", full)
+	output, lddErr := cmd.CombinedOutput()
+	lddOut := string(output)
+	if strings.Contains(lddOut, "statically linked") || strings.Contains(lddOut, "not a dynamic executable") {
+		// Static binary - no shared library dependencies to record.
+		lddErr = nil
+		ret.ExecutableType = ExecutableTypeStaticBinary
+	} else {
+		ret.ExecutableType = ExecutableTypeDynamicBinary
+	}
+	if lddErr != nil && len(lddOut) == 0 {
+		return ret, fmt.Errorf("running ldd on %q: %w", full, lddErr)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(lddOut))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "ldd:") {
+			// Warning/error from ldd itself; surface as a dependency error for visibility.
+			ret.Dependencies = append(ret.Dependencies, ExecutableResult{Error: line})
+			continue
+		}
+
+		var dep ExecutableResult
+
+		if strings.Contains(line, "=>") {
+			parts := strings.SplitN(line, "=>", 2)
+			left := strings.TrimSpace(parts[0])
+			right := strings.TrimSpace(parts[1])
+			if strings.Contains(right, "not found") {
+				dep.FullPath = left
+				dep.Error = fmt.Sprintf("dependency missing: %s", line)
+			} else {
+				fields := strings.Fields(right)
+				if len(fields) > 0 && strings.HasPrefix(fields[0], "/") {
+					dep.FullPath = fields[0]
+					if _, err := os.Stat(fields[0]); err != nil {
+						dep.Error = fmt.Sprintf("stat %q: %v", fields[0], err)
+					}
+				}
+			}
+		} else {
+			fields := strings.Fields(line)
+			if len(fields) > 0 && strings.HasPrefix(fields[0], "/") {
+				dep.FullPath = fields[0]
+				if _, err := os.Stat(fields[0]); err != nil {
+					dep.Error = fmt.Sprintf("stat %q: %v", fields[0], err)
+				}
+			} else {
+				continue
+			}
+		}
+
+		ret.Dependencies = append(ret.Dependencies, dep)
+	}
+	if err := scanner.Err(); err != nil {
+		return ret, fmt.Errorf("parsing ldd output for %q: %w", full, err)
+	}
+	if lddErr != nil {
+		return ret, fmt.Errorf("running ldd on %q: %w", full, lddErr)
+	}
+
+	return ret, nil
+}
+
 func (ct *containerTester) run() error {
 	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 
 	captureOutput := fs.Bool("capture-output", false, "Capture output of running each executable")
 	deployBins := fs.String("deploy-bins", os.Getenv("DEPLOY_BINS"), "Colon-separated list of binaries to test")
 	deployPaths := fs.String("deploy-paths", os.Getenv("DEPLOY_PATHS"), "Colon-separated list of paths to search for executables to test")
+	useLdd := fs.Bool("use-ldd", false, "Shell out to ldd instead of the in-process debug/elf analysis (kept for comparison)")
+	parallel := fs.Int("parallel", runtime.NumCPU(), "Number of executables to test concurrently")
+	testSpecPath := fs.String("test-spec", "", "Path to a JSON or YAML test-spec file (.yaml/.yml parses as YAML) mapping binary/script names to acceptance test cases")
+	keepGoing := fs.Bool("keep-going", false, "Keep running test cases after a failure instead of stopping early")
+	maxShebangDepth := fs.Int("max-shebang-depth", defaultMaxShebangDepth, "Maximum number of script -> interpreter hops to follow in a shebang chain")
 
 	if err := fs.Parse(os.Args[1:]); err != nil {
 		return fmt.Errorf("parsing flags: %w", err)
 	}
 
 	ct.captureOutput = *captureOutput
+	ct.useLdd = *useLdd
+	ct.parallel = *parallel
+	if ct.parallel < 1 {
+		ct.parallel = 1
+	}
+	ct.keepGoing = *keepGoing
+	ct.maxShebangDepth = *maxShebangDepth
+	if *testSpecPath != "" {
+		spec, err := loadTestSpec(*testSpecPath)
+		if err != nil {
+			return err
+		}
+		ct.testSpec = spec
+	}
 
 	deployBinsList := strings.Split(*deployBins, ":")
 	deployPathsList := strings.Split(*deployPaths, ":")
@@ -255,13 +423,16 @@ func (ct *containerTester) run() error {
 		Executables: make(map[string]ExecutableResult),
 	}
 
-	for _, bin := range deployBinsList {
-		res, err := ct.testExecutable(bin, true)
-		if err != nil {
-			res.Error = err.Error()
-		}
+	// key is the name under which the result is recorded in
+	// results.Executables; name is what's passed to testExecutable.
+	type scanJob struct {
+		key  string
+		name string
+	}
 
-		results.Executables[bin] = res
+	var jobs []scanJob
+	for _, bin := range deployBinsList {
+		jobs = append(jobs, scanJob{key: bin, name: bin})
 	}
 
 	for _, path := range deployPathsList {
@@ -287,19 +458,68 @@ func (ct *containerTester) run() error {
 				continue
 			}
 
-			res, err := ct.testExecutable(filepath.Join(path, file.Name()), true)
-			if err != nil {
-				res.Error = err.Error()
-			}
-
-			results.Executables[file.Name()] = res
+			jobs = append(jobs, scanJob{key: file.Name(), name: filepath.Join(path, file.Name())})
 		}
 	}
 
+	// Fan the jobs out over a bounded worker pool; shebang/ELF analysis is
+	// I/O-bound so this is a straightforward speedup over the old strictly
+	// sequential loop. testExecutable itself serializes captureOutput
+	// execution per full path, and each job keeps its own 5-second timeout.
+	jobCh := make(chan scanJob)
+	var resultsMu sync.Mutex
+	var wg sync.WaitGroup
+	var anyFailed, stopEarly atomic.Bool
+	for i := 0; i < ct.parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobCh {
+				if stopEarly.Load() {
+					resultsMu.Lock()
+					results.Executables[j.key] = ExecutableResult{Error: "skipped: stopped after earlier failure"}
+					resultsMu.Unlock()
+					continue
+				}
+
+				res, err := ct.testExecutable(j.name, true)
+				if err != nil {
+					res.Error = err.Error()
+				}
+
+				if tc, ok := ct.testSpec[j.key]; ok && res.FullPath != "" {
+					tcRes := ct.runTestCase(res.FullPath, tc)
+					res.TestCase = &tcRes
+					if !tcRes.Passed {
+						anyFailed.Store(true)
+						if !ct.keepGoing {
+							stopEarly.Store(true)
+						}
+					}
+				}
+
+				resultsMu.Lock()
+				results.Executables[j.key] = res
+				resultsMu.Unlock()
+			}
+		}()
+	}
+	for _, j := range jobs {
+		jobCh <- j
+	}
+	close(jobCh)
+	wg.Wait()
+
+	// encoding/json sorts map keys when marshaling, so the output below is
+	// deterministic regardless of the order jobs finished in.
 	if err := json.NewEncoder(os.Stdout).Encode(results); err != nil {
 		return fmt.Errorf("encoding test results: %w", err)
 	}
 
+	if anyFailed.Load() {
+		return fmt.Errorf("one or more test-spec cases failed")
+	}
+
 	return nil
 }
 
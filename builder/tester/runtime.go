@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RuntimeInfo records the version and module/library search path reported
+// by a known language runtime's own interpreter binary.
+type RuntimeInfo struct {
+	Runtime    string   `json:",omitempty"`
+	Version    string   `json:",omitempty"`
+	ModulePath []string `json:",omitempty"`
+}
+
+var runtimeBasenamePatterns = map[string]*regexp.Regexp{
+	"python": regexp.MustCompile(`^python[0-9.]*$`),
+	"perl":   regexp.MustCompile(`^perl[0-9.]*$`),
+	"ruby":   regexp.MustCompile(`^ruby[0-9.]*$`),
+	"node":   regexp.MustCompile(`^node(js)?$`),
+}
+
+// knownRuntimeFor returns the runtime name probeRuntime understands for
+// full's basename, or "" if it isn't a recognized language runtime.
+func knownRuntimeFor(full string) string {
+	base := filepath.Base(full)
+	for rt, pattern := range runtimeBasenamePatterns {
+		if pattern.MatchString(base) {
+			return rt
+		}
+	}
+	return ""
+}
+
+// probeRuntime runs a short, runtime-specific introspection one-liner to
+// recover the interpreter's version and module search path, under the same
+// 5 second timeout used elsewhere in this package for running executables.
+func probeRuntime(full, rt string) (*RuntimeInfo, error) {
+	var args []string
+	switch rt {
+	case "python":
+		args = []string{"-c", `import sys,json;print(json.dumps({'v':sys.version,'p':sys.path}))`}
+	case "perl":
+		args = []string{"-e", `print "$^V\n"; print join(":", @INC), "\n";`}
+	case "ruby":
+		args = []string{"-e", `puts RUBY_VERSION; puts $LOAD_PATH.join(":")`}
+	case "node":
+		args = []string{"-p", `JSON.stringify({v: process.version, p: require('module').globalPaths})`}
+	default:
+		return nil, fmt.Errorf("no introspection command for runtime %q", rt)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, full, args...)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("probing %s runtime %q: %w", rt, full, err)
+	}
+
+	info := &RuntimeInfo{Runtime: rt}
+	switch rt {
+	case "python", "node":
+		var decoded struct {
+			V string   `json:"v"`
+			P []string `json:"p"`
+		}
+		if err := json.Unmarshal(stdout.Bytes(), &decoded); err != nil {
+			return nil, fmt.Errorf("parsing %s introspection output: %w", rt, err)
+		}
+		info.Version = decoded.V
+		info.ModulePath = decoded.P
+	case "perl", "ruby":
+		lines := strings.SplitN(strings.TrimRight(stdout.String(), "\n"), "\n", 2)
+		if len(lines) > 0 {
+			info.Version = strings.TrimSpace(lines[0])
+		}
+		if len(lines) > 1 {
+			info.ModulePath = strings.Split(strings.TrimSpace(lines[1]), ":")
+		}
+	}
+
+	return info, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeShebangScript writes an executable script at dir/name whose shebang
+// line invokes target (another name in dir, resolved via PATH).
+func writeShebangScript(t *testing.T, dir, name, target string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	body := fmt.Sprintf("#!%s\n", filepath.Join(dir, target))
+	if err := os.WriteFile(path, []byte(body), 0o755); err != nil {
+		t.Fatalf("write script %q: %v", name, err)
+	}
+}
+
+func TestResolveExecutableDetectsDirectCycle(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PATH", dir)
+
+	// a shebangs to b, b shebangs back to a.
+	writeShebangScript(t, dir, "a", "b")
+	writeShebangScript(t, dir, "b", "a")
+
+	ct := &containerTester{}
+	res, err := ct.testExecutable("a", true)
+	if err != nil {
+		t.Fatalf("testExecutable: %v", err)
+	}
+	// The cycle is detected partway down the shebang chain, which surfaces as
+	// an Error on the nested dependency that triggered it rather than failing
+	// the top-level call outright (resolveExecutable only fails its caller on
+	// lookup/stat errors, not on errors from a dependency it's merely
+	// describing).
+	dep := res
+	for {
+		if dep.Error != "" {
+			if !contains(dep.Error, "cycle detected") {
+				t.Errorf("Error = %q, want it to mention a cycle", dep.Error)
+			}
+			return
+		}
+		if len(dep.Dependencies) == 0 {
+			t.Fatal("walked the whole chain without finding a cycle-detected error")
+		}
+		dep = dep.Dependencies[0]
+	}
+}
+
+func TestResolveExecutableExceedsMaxDepth(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PATH", dir)
+
+	// Build a chain script0 -> script1 -> ... -> scriptN, each a distinct
+	// file, longer than maxShebangDepth so it's rejected even though it
+	// never revisits a path.
+	const chainLen = 5
+	for i := 0; i < chainLen; i++ {
+		name := fmt.Sprintf("script%d", i)
+		target := fmt.Sprintf("script%d", i+1)
+		writeShebangScript(t, dir, name, target)
+	}
+	// Terminal script does nothing.
+	if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("script%d", chainLen)), []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatalf("write terminal script: %v", err)
+	}
+
+	ct := &containerTester{maxShebangDepth: 2}
+	res, err := ct.testExecutable("script0", true)
+	if err != nil {
+		t.Fatalf("testExecutable: %v", err)
+	}
+	dep := res
+	for {
+		if dep.Error != "" {
+			if !contains(dep.Error, "exceeds max depth") {
+				t.Errorf("Error = %q, want it to mention exceeding max depth", dep.Error)
+			}
+			return
+		}
+		if len(dep.Dependencies) == 0 {
+			t.Fatal("walked the whole chain without finding a max-depth error")
+		}
+		dep = dep.Dependencies[0]
+	}
+}
+
+func contains(s, substr string) bool {
+	return strings.Contains(s, substr)
+}
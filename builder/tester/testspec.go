@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TestSpec maps a deploy-bins/deploy-paths key (the same key results are
+// recorded under in TestResults.Executables) to the acceptance test case
+// that should be run against it. This turns -capture-output's unstructured
+// dump into a real pass/fail harness, borrowing the idea of directives
+// driving expected behavior from Go's test/run.go but expressed as data
+// loaded from a file instead of comments.
+type TestSpec map[string]TestCase
+
+// TestCase is a declarative expectation for running one executable. Field
+// names are kept identical (capitalized) between the JSON and YAML forms -
+// yaml.v3 lowercases field names by default, so each field carries an
+// explicit yaml tag matching its JSON key.
+type TestCase struct {
+	Args []string `json:",omitempty" yaml:"Args,omitempty"`
+
+	Stdin string `json:",omitempty" yaml:"Stdin,omitempty"`
+
+	// ExpectExitCode defaults to 0 when omitted.
+	ExpectExitCode int `json:",omitempty" yaml:"ExpectExitCode,omitempty"`
+
+	// ExpectStdout and ExpectStderr are regular expressions matched against
+	// the captured output; an empty pattern means "don't check".
+	ExpectStdout string `json:",omitempty" yaml:"ExpectStdout,omitempty"`
+	ExpectStderr string `json:",omitempty" yaml:"ExpectStderr,omitempty"`
+
+	// TimeoutSeconds overrides the default 5 second execution timeout.
+	TimeoutSeconds float64 `json:",omitempty" yaml:"TimeoutSeconds,omitempty"`
+
+	// RequireEnv lists environment variables that must already be set in
+	// this process's environment for the case to run; any missing one
+	// fails the case without executing the binary.
+	RequireEnv []string `json:",omitempty" yaml:"RequireEnv,omitempty"`
+}
+
+// TestCaseResult records the outcome of running a TestCase against its
+// executable.
+type TestCaseResult struct {
+	Passed     bool   `json:",omitempty"`
+	Diff       string `json:",omitempty"`
+	DurationMs int64  `json:",omitempty"`
+}
+
+// loadTestSpec reads a JSON or YAML test-spec file mapping binary/script
+// names to their TestCase. The format is chosen by extension: .yaml/.yml
+// parses as YAML, everything else (including .json and extensionless
+// paths) parses as JSON.
+func loadTestSpec(path string) (TestSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading test spec %q: %w", path, err)
+	}
+
+	var spec TestSpec
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &spec); err != nil {
+			return nil, fmt.Errorf("parsing test spec %q: %w", path, err)
+		}
+	default:
+		if err := json.Unmarshal(data, &spec); err != nil {
+			return nil, fmt.Errorf("parsing test spec %q: %w", path, err)
+		}
+	}
+	return spec, nil
+}
+
+// runTestCase executes full with tc's argv/stdin and compares the result
+// against tc's expectations.
+func (ct *containerTester) runTestCase(full string, tc TestCase) TestCaseResult {
+	start := time.Now()
+
+	var diffs []string
+	for _, name := range tc.RequireEnv {
+		if _, ok := os.LookupEnv(name); !ok {
+			diffs = append(diffs, fmt.Sprintf("required env var %q is not set", name))
+		}
+	}
+	if len(diffs) > 0 {
+		return TestCaseResult{Diff: strings.Join(diffs, "; ")}
+	}
+
+	timeout := 5 * time.Second
+	if tc.TimeoutSeconds > 0 {
+		timeout = time.Duration(tc.TimeoutSeconds * float64(time.Second))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, full, tc.Args...)
+	if tc.Stdin != "" {
+		cmd.Stdin = strings.NewReader(tc.Stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+	duration := time.Since(start)
+
+	exitCode := 0
+	if runErr != nil {
+		if exitErr, ok := runErr.(*exec.ExitError); ok {
+			exitCode = exitErr.ExitCode()
+		} else {
+			diffs = append(diffs, fmt.Sprintf("running %q: %v", full, runErr))
+		}
+	}
+	if exitCode != tc.ExpectExitCode {
+		diffs = append(diffs, fmt.Sprintf("exit code %d, want %d", exitCode, tc.ExpectExitCode))
+	}
+
+	if tc.ExpectStdout != "" {
+		if ok, err := matchTestCasePattern(tc.ExpectStdout, stdout.String()); err != nil {
+			diffs = append(diffs, fmt.Sprintf("stdout pattern %q: %v", tc.ExpectStdout, err))
+		} else if !ok {
+			diffs = append(diffs, fmt.Sprintf("stdout %q does not match pattern %q", stdout.String(), tc.ExpectStdout))
+		}
+	}
+	if tc.ExpectStderr != "" {
+		if ok, err := matchTestCasePattern(tc.ExpectStderr, stderr.String()); err != nil {
+			diffs = append(diffs, fmt.Sprintf("stderr pattern %q: %v", tc.ExpectStderr, err))
+		} else if !ok {
+			diffs = append(diffs, fmt.Sprintf("stderr %q does not match pattern %q", stderr.String(), tc.ExpectStderr))
+		}
+	}
+
+	return TestCaseResult{
+		Passed:     len(diffs) == 0,
+		Diff:       strings.Join(diffs, "; "),
+		DurationMs: duration.Milliseconds(),
+	}
+}
+
+func matchTestCasePattern(pattern, s string) (bool, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(s), nil
+}
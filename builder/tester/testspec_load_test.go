@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadTestSpecJSON(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "spec.json")
+	const data = `{"/usr/bin/tool": {"Args": ["--version"], "ExpectExitCode": 0, "ExpectStdout": "^1\\."}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+
+	spec, err := loadTestSpec(path)
+	if err != nil {
+		t.Fatalf("loadTestSpec: %v", err)
+	}
+	want := TestSpec{"/usr/bin/tool": TestCase{Args: []string{"--version"}, ExpectStdout: "^1\\."}}
+	if !reflect.DeepEqual(spec, want) {
+		t.Errorf("spec = %+v, want %+v", spec, want)
+	}
+}
+
+func TestLoadTestSpecYAML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "spec.yaml")
+	const data = `
+/usr/bin/tool:
+  Args: ["--version"]
+  ExpectExitCode: 0
+  ExpectStdout: "^1\\."
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+
+	spec, err := loadTestSpec(path)
+	if err != nil {
+		t.Fatalf("loadTestSpec: %v", err)
+	}
+	want := TestSpec{"/usr/bin/tool": TestCase{Args: []string{"--version"}, ExpectStdout: "^1\\."}}
+	if !reflect.DeepEqual(spec, want) {
+		t.Errorf("spec = %+v, want %+v", spec, want)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestScript writes an executable shell script to dir/name containing
+// body and returns its path.
+func writeTestScript(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatalf("write script %q: %v", name, err)
+	}
+	return path
+}
+
+func TestRunTestCase(t *testing.T) {
+	dir := t.TempDir()
+
+	cases := []struct {
+		name   string
+		script string
+		tc     TestCase
+		env    map[string]string
+		want   bool
+		diffRe string
+	}{
+		{
+			name:   "exit code matches",
+			script: "exit 0\n",
+			tc:     TestCase{ExpectExitCode: 0},
+			want:   true,
+		},
+		{
+			name:   "exit code mismatch",
+			script: "exit 1\n",
+			tc:     TestCase{ExpectExitCode: 0},
+			want:   false,
+			diffRe: "exit code 1, want 0",
+		},
+		{
+			name:   "stdout matches pattern",
+			script: "echo hello world\n",
+			tc:     TestCase{ExpectStdout: "^hello"},
+			want:   true,
+		},
+		{
+			name:   "stdout does not match pattern",
+			script: "echo goodbye\n",
+			tc:     TestCase{ExpectStdout: "^hello"},
+			want:   false,
+			diffRe: "does not match pattern",
+		},
+		{
+			name:   "stderr matches pattern",
+			script: "echo oops 1>&2\n",
+			tc:     TestCase{ExpectStderr: "oops"},
+			want:   true,
+		},
+		{
+			name:   "missing required env var fails without running",
+			script: "exit 1\n", // would fail the exit code check too, but env check should short-circuit first
+			tc:     TestCase{RequireEnv: []string{"TESTER_TEST_MISSING_VAR"}},
+			want:   false,
+			diffRe: `required env var "TESTER_TEST_MISSING_VAR" is not set`,
+		},
+	}
+
+	ct := &containerTester{}
+	for i, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			script := writeTestScript(t, dir, filepath.Base(t.Name())+string(rune('a'+i)), tc.script)
+			res := ct.runTestCase(script, tc.tc)
+			if res.Passed != tc.want {
+				t.Errorf("Passed = %v (diff %q), want %v", res.Passed, res.Diff, tc.want)
+			}
+			if tc.diffRe != "" && !regexpMatch(t, tc.diffRe, res.Diff) {
+				t.Errorf("Diff = %q, want it to contain %q", res.Diff, tc.diffRe)
+			}
+		})
+	}
+}
+
+func regexpMatch(t *testing.T, pattern, s string) bool {
+	t.Helper()
+	ok, err := matchTestCasePattern(pattern, s)
+	if err != nil {
+		t.Fatalf("matchTestCasePattern: %v", err)
+	}
+	return ok
+}
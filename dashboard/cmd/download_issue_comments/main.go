@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -13,13 +12,15 @@ import (
 	"time"
 
 	"github.com/google/go-github/v61/github"
-	"golang.org/x/oauth2"
+
+	"github.com/neurodesk/dashboard/internal/gh"
 )
 
 func main() {
 	issueNumber := flag.Int("issue", 0, "GitHub issue number to download")
 	repoFlag := flag.String("repo", defaultRepo(), "GitHub repository in owner/repo form")
 	outputFlag := flag.String("out", "", "Output file path (defaults to local/issue-<n>-comments.json)")
+	baseURL := flag.String("github-base-url", "", "GitHub Enterprise base URL (defaults to GITHUB_BASE_URL, falls back to github.com)")
 	flag.Parse()
 
 	if *issueNumber <= 0 {
@@ -32,7 +33,7 @@ func main() {
 	}
 
 	ctx := context.Background()
-	client, err := newGitHubClient(ctx)
+	client, err := gh.NewClient(ctx, gh.ClientOptions{BaseURL: *baseURL})
 	if err != nil {
 		log.Fatalf("github client: %v", err)
 	}
@@ -42,7 +43,7 @@ func main() {
 		log.Fatalf("fetch issue: %v", err)
 	}
 
-	comments, err := listAllIssueComments(ctx, client, owner, repo, *issueNumber)
+	comments, err := gh.ListAllIssueComments(ctx, client, owner, repo, *issueNumber)
 	if err != nil {
 		log.Fatalf("fetch comments: %v", err)
 	}
@@ -144,37 +145,3 @@ func parseRepo(repo string) (string, string, error) {
 	}
 	return parts[0], parts[1], nil
 }
-
-func newGitHubClient(ctx context.Context) (*github.Client, error) {
-	if ctx == nil {
-		return nil, errors.New("context is required")
-	}
-
-	token := strings.TrimSpace(os.Getenv("GITHUB_TOKEN"))
-	if token == "" {
-		return github.NewClient(nil), nil
-	}
-
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(ctx, ts)
-	return github.NewClient(tc), nil
-}
-
-func listAllIssueComments(ctx context.Context, client *github.Client, owner, repo string, number int) ([]*github.IssueComment, error) {
-	opt := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
-
-	var comments []*github.IssueComment
-	for {
-		batch, resp, err := client.Issues.ListComments(ctx, owner, repo, number, opt)
-		if err != nil {
-			return nil, fmt.Errorf("list comments: %w", err)
-		}
-		comments = append(comments, batch...)
-		if resp == nil || resp.NextPage == 0 {
-			break
-		}
-		opt.Page = resp.NextPage
-	}
-
-	return comments, nil
-}
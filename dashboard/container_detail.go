@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v61/github"
+)
+
+// TestRunHistoryEntry is a single historical test run recorded for a
+// container, accumulated across dashboard builds.
+type TestRunHistoryEntry struct {
+	RunID     int64
+	RanAt     time.Time
+	Status    TestRunStatus
+	Details   string
+	CommitSHA string
+}
+
+// TestRunHistory is the rolling, newest-first history of test runs for one
+// container, persisted to data/test_history/<container>.json.
+type TestRunHistory struct {
+	Container string
+	Entries   []TestRunHistoryEntry
+}
+
+// defaultHistoryCap bounds how many historical runs are retained per
+// container so the history files don't grow unbounded.
+const defaultHistoryCap = 25
+
+func testHistoryPath(container string) string {
+	return filepath.Join("data", "test_history", container+".json")
+}
+
+func loadTestRunHistory(container string) (TestRunHistory, error) {
+	history := TestRunHistory{Container: container}
+	bytes, err := os.ReadFile(testHistoryPath(container))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return history, err
+	}
+	if err := json.Unmarshal(bytes, &history); err != nil {
+		return history, err
+	}
+	return history, nil
+}
+
+func saveTestRunHistory(history TestRunHistory) error {
+	path := testHistoryPath(history.Container)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// recordTestRun merges a freshly observed run into the container's history,
+// deduping by RunID, sorting newest-first, and capping at defaultHistoryCap.
+func recordTestRun(container string, entry TestRunHistoryEntry) (TestRunHistory, error) {
+	history, err := loadTestRunHistory(container)
+	if err != nil {
+		return history, err
+	}
+
+	replaced := false
+	for i, existing := range history.Entries {
+		if existing.RunID == entry.RunID {
+			history.Entries[i] = entry
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		history.Entries = append(history.Entries, entry)
+	}
+
+	sort.Slice(history.Entries, func(i, j int) bool {
+		return history.Entries[i].RanAt.After(history.Entries[j].RanAt)
+	})
+	if len(history.Entries) > defaultHistoryCap {
+		history.Entries = history.Entries[:defaultHistoryCap]
+	}
+
+	if err := saveTestRunHistory(history); err != nil {
+		return history, err
+	}
+	return history, nil
+}
+
+// maxWorkflowRunPages bounds how far listAllWorkflowRuns paginates. The
+// GitHub API returns workflow runs newest-first, and callers only need the
+// most recent ones to find the latest RunStartedAt, so walking the entire
+// history of a long-lived repository would just burn rate limit for no
+// benefit.
+const maxWorkflowRunPages = 3
+
+// listAllWorkflowRuns pages through the most recent of a repository's GitHub
+// Actions runs, following the same next-page pattern as
+// gh.ListAllIssueComments but capped at maxWorkflowRunPages.
+func listAllWorkflowRuns(ctx context.Context, client *github.Client, owner, repo string) ([]*github.WorkflowRun, error) {
+	opt := &github.ListWorkflowRunsOptions{
+		ListOptions: github.ListOptions{PerPage: 50},
+	}
+
+	var runs []*github.WorkflowRun
+	for page := 0; page < maxWorkflowRunPages; page++ {
+		batch, resp, err := client.Actions.ListRepositoryWorkflowRuns(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, fmt.Errorf("list workflow runs: %w", err)
+		}
+		runs = append(runs, batch.WorkflowRuns...)
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return runs, nil
+}
+
+// syncTestRunHistories fetches recent workflow runs and appends one history
+// entry per container whose latest parsed TestRunEntry we already know
+// about, so successive dashboard builds accumulate a timeline instead of
+// overwriting it with only the most recent aggregated report.
+func syncTestRunHistories(ctx context.Context, report *TestRunReport) {
+	if report == nil {
+		return
+	}
+
+	owner, repo := gitHubRepo()
+	client, err := newGitHubClient(ctx)
+	if err != nil {
+		return
+	}
+
+	runs, err := listAllWorkflowRuns(ctx, client, owner, repo)
+	if err != nil {
+		return
+	}
+	var latest *github.WorkflowRun
+	for _, run := range runs {
+		if latest == nil || run.GetRunStartedAt().After(latest.GetRunStartedAt().Time) {
+			latest = run
+		}
+	}
+
+	for container, entry := range report.Entries {
+		historyEntry := TestRunHistoryEntry{
+			Status:  entry.Status,
+			Details: entry.Details,
+			RanAt:   report.CreatedAt,
+		}
+		if latest != nil {
+			historyEntry.RunID = latest.GetID()
+			historyEntry.CommitSHA = latest.GetHeadSHA()
+		}
+		if _, err := recordTestRun(container, historyEntry); err != nil {
+			continue
+		}
+	}
+}
+
+type containerPageData struct {
+	Container ContainerProgress
+	History   TestRunHistory
+
+	// Flakes maps test name to its aggregated history for this container,
+	// populated from the TestRunReport's FlakeHistory when available.
+	Flakes map[string]TestHistoryEntry
+}
+
+// buildContainerPageData loads the on-disk test-run history for progress and
+// assembles the data the container.html template needs.
+func buildContainerPageData(progress ContainerProgress, flakes *FlakeHistory) (containerPageData, error) {
+	history, err := loadTestRunHistory(progress.Name)
+	if err != nil {
+		return containerPageData{}, err
+	}
+
+	data := containerPageData{Container: progress, History: history}
+	if flakes != nil {
+		data.Flakes = flakes.Entries[progress.Name]
+	}
+	return data, nil
+}
+
+// renderContainerPageHTML executes the container.html template for data into w.
+func renderContainerPageHTML(w io.Writer, data containerPageData) error {
+	return templates.ExecuteTemplate(w, "container.html", data)
+}
+
+// renderContainerPage writes containers/<name>.html with every known
+// version, its source, the accumulated test-run timeline, and the full
+// details of the latest run.
+func renderContainerPage(outDir string, progress ContainerProgress, flakes *FlakeHistory) error {
+	data, err := buildContainerPageData(progress, flakes)
+	if err != nil {
+		return err
+	}
+
+	containersDir := filepath.Join(outDir, "containers")
+	if err := os.MkdirAll(containersDir, 0o755); err != nil {
+		return err
+	}
+
+	dest := filepath.Join(containersDir, progress.Name+".html")
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return renderContainerPageHTML(f, data)
+}
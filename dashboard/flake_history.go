@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v61/github"
+
+	"github.com/neurodesk/dashboard/internal/gh"
+)
+
+// defaultFlakeWindow bounds how far back loadFlakeHistory looks for
+// "Container test run" issues, matching the lookback default used by
+// golang.org/x/build/cmd/watchflakes.
+const defaultFlakeWindow = 45 * 24 * time.Hour
+
+// flakeConsecutiveThreshold is how many consecutive failing runs, counting
+// back from the most recent, turn a test that has also passed in the window
+// into "stable-fail" rather than "flaky": a long failing streak means
+// something broke, not that the test is unreliable.
+const flakeConsecutiveThreshold = 3
+
+// FlakeClassification summarizes a test's reliability over a loadFlakeHistory window.
+type FlakeClassification string
+
+const (
+	FlakeClassificationNew        FlakeClassification = "new"
+	FlakeClassificationStablePass FlakeClassification = "stable-pass"
+	FlakeClassificationStableFail FlakeClassification = "stable-fail"
+	FlakeClassificationFlaky      FlakeClassification = "flaky"
+)
+
+// TestHistoryEntry aggregates one container+test's outcomes across every
+// "Container test run" issue observed by loadFlakeHistory.
+type TestHistoryEntry struct {
+	Container string
+	Test      string
+
+	Passed  int
+	Failed  int
+	Skipped int
+
+	FlakeRate   float64
+	Consecutive int
+
+	Classification FlakeClassification
+
+	LastFailureIssueNumber int
+	LastFailureIssueURL    string
+}
+
+// Runs is the total number of passed/failed/skipped observations behind
+// this entry, the denominator for badges like "flaky (3/12 runs failed)".
+func (e TestHistoryEntry) Runs() int {
+	return e.Passed + e.Failed + e.Skipped
+}
+
+// FlakeHistory is the aggregated, per container+test outcome history across
+// every "Container test run" issue created within Window of Since.
+type FlakeHistory struct {
+	Since  time.Time
+	Window time.Duration
+
+	// Entries[container][test] holds that pair's aggregated outcome.
+	Entries map[string]map[string]TestHistoryEntry
+}
+
+// testRunObservation is one issue's recorded outcome for a single
+// container+test pair, kept only long enough to compute a TestHistoryEntry.
+type testRunObservation struct {
+	createdAt   time.Time
+	issueNumber int
+	issueURL    string
+	status      TestRunStatus
+}
+
+// loadFlakeHistory walks every "Container test run" issue created within
+// window (default defaultFlakeWindow) and aggregates each test's outcomes
+// across runs, classifying flaky tests along the way. refresh bypasses the
+// on-disk report cache, which is what makes this affordable to call on
+// every dashboard rebuild despite potentially walking hundreds of issues.
+func loadFlakeHistory(ctx context.Context, window time.Duration, refresh bool) (*FlakeHistory, error) {
+	if window <= 0 {
+		window = defaultFlakeWindow
+	}
+
+	owner, repo := gh.GitHubRepo()
+
+	client, err := newGitHubClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().Add(-window)
+	issues, err := listTestRunIssuesSince(ctx, client, owner, repo, since)
+	if err != nil {
+		return nil, err
+	}
+
+	observations := make(map[string]map[string][]testRunObservation)
+	for _, issue := range issues {
+		comments, err := fetchIssueComments(ctx, client, owner, repo, issue, refresh)
+		if err != nil {
+			return nil, fmt.Errorf("list comments for issue #%d: %w", issue.GetNumber(), err)
+		}
+
+		for container, entry := range parseTestRunEntries(comments) {
+			for _, test := range entry.Tests {
+				if observations[container] == nil {
+					observations[container] = make(map[string][]testRunObservation)
+				}
+				observations[container][test.Name] = append(observations[container][test.Name], testRunObservation{
+					createdAt:   issue.GetCreatedAt().Time,
+					issueNumber: issue.GetNumber(),
+					issueURL:    issue.GetHTMLURL(),
+					status:      test.Status,
+				})
+			}
+		}
+	}
+
+	history := &FlakeHistory{
+		Since:   since,
+		Window:  window,
+		Entries: make(map[string]map[string]TestHistoryEntry),
+	}
+	for container, tests := range observations {
+		history.Entries[container] = make(map[string]TestHistoryEntry)
+		for test, runs := range tests {
+			sort.Slice(runs, func(i, j int) bool { return runs[i].createdAt.After(runs[j].createdAt) })
+			history.Entries[container][test] = summarizeTestHistory(container, test, runs)
+		}
+	}
+
+	return history, nil
+}
+
+// summarizeTestHistory reduces runs (newest-first) to a single
+// TestHistoryEntry.
+func summarizeTestHistory(container, test string, runs []testRunObservation) TestHistoryEntry {
+	entry := TestHistoryEntry{Container: container, Test: test}
+
+	consecutive := 0
+	inStreak := true
+	for _, run := range runs {
+		switch run.status {
+		case TestRunStatusPassed:
+			entry.Passed++
+		case TestRunStatusFailed:
+			entry.Failed++
+			if entry.LastFailureIssueNumber == 0 {
+				entry.LastFailureIssueNumber = run.issueNumber
+				entry.LastFailureIssueURL = run.issueURL
+			}
+		case TestRunStatusSkipped:
+			entry.Skipped++
+		}
+
+		if inStreak {
+			if run.status == TestRunStatusFailed {
+				consecutive++
+			} else {
+				inStreak = false
+			}
+		}
+	}
+	entry.Consecutive = consecutive
+
+	if total := entry.Passed + entry.Failed; total > 0 {
+		entry.FlakeRate = float64(entry.Failed) / float64(total)
+	}
+
+	switch {
+	case entry.Passed+entry.Failed <= 1:
+		entry.Classification = FlakeClassificationNew
+	case entry.Failed == 0:
+		entry.Classification = FlakeClassificationStablePass
+	case entry.Passed == 0:
+		entry.Classification = FlakeClassificationStableFail
+	case entry.Consecutive >= flakeConsecutiveThreshold:
+		entry.Classification = FlakeClassificationStableFail
+	default:
+		entry.Classification = FlakeClassificationFlaky
+	}
+
+	return entry
+}
+
+// listTestRunIssuesSince pages through "Container test run" issues sorted
+// newest-first, stopping once an issue's creation date falls before since
+// rather than after a fixed page count - the window can span anywhere from
+// a handful of runs to hundreds depending on build cadence.
+func listTestRunIssuesSince(ctx context.Context, client *github.Client, owner, repo string, since time.Time) ([]*github.Issue, error) {
+	const titlePrefix = "Container test run"
+
+	listOpt := &github.IssueListByRepoOptions{
+		State:     "all",
+		Sort:      "created",
+		Direction: "desc",
+		ListOptions: github.ListOptions{
+			PerPage: 20,
+		},
+	}
+
+	var issues []*github.Issue
+	for {
+		batch, resp, err := client.Issues.ListByRepo(ctx, owner, repo, listOpt)
+		if err != nil {
+			return nil, fmt.Errorf("list issues: %w", err)
+		}
+
+		stop := false
+		for _, issue := range batch {
+			if issue.IsPullRequest() {
+				continue
+			}
+			if issue.GetCreatedAt().Time.Before(since) {
+				stop = true
+				break
+			}
+			if strings.HasPrefix(issue.GetTitle(), titlePrefix) {
+				issues = append(issues, issue)
+			}
+		}
+
+		if stop || resp == nil || resp.NextPage == 0 {
+			break
+		}
+		listOpt.Page = resp.NextPage
+	}
+
+	return issues, nil
+}
+
+// attachFlakeHistory best-effort populates report.FlakeHistory; a failure
+// here (rate limiting, network) shouldn't prevent the dashboard from
+// rendering the latest run it already has.
+func attachFlakeHistory(ctx context.Context, report *TestRunReport, refresh bool) {
+	if report == nil {
+		return
+	}
+
+	history, err := loadFlakeHistory(ctx, defaultFlakeWindow, refresh)
+	if err != nil {
+		slog.Warn("load flake history", "error", err)
+		return
+	}
+	report.FlakeHistory = history
+}
@@ -0,0 +1,66 @@
+package main
+
+import "testing"
+
+func TestSummarizeTestHistoryClassification(t *testing.T) {
+	mk := func(statuses ...TestRunStatus) []testRunObservation {
+		var runs []testRunObservation
+		for i, s := range statuses {
+			runs = append(runs, testRunObservation{issueNumber: i + 1, status: s})
+		}
+		return runs
+	}
+
+	cases := []struct {
+		name  string
+		runs  []testRunObservation
+		want  FlakeClassification
+		flake float64
+	}{
+		{
+			name: "single run is new",
+			runs: mk(TestRunStatusFailed),
+			want: FlakeClassificationNew,
+		},
+		{
+			name: "always passed",
+			runs: mk(TestRunStatusPassed, TestRunStatusPassed, TestRunStatusPassed),
+			want: FlakeClassificationStablePass,
+		},
+		{
+			name: "always failed",
+			runs: mk(TestRunStatusFailed, TestRunStatusFailed),
+			want: FlakeClassificationStableFail,
+		},
+		{
+			name:  "mixed but not currently streaking",
+			runs:  mk(TestRunStatusPassed, TestRunStatusFailed, TestRunStatusPassed),
+			want:  FlakeClassificationFlaky,
+			flake: 1.0 / 3.0,
+		},
+		{
+			name: "recovered from an old failure but now failing a long streak",
+			runs: mk(TestRunStatusFailed, TestRunStatusFailed, TestRunStatusFailed, TestRunStatusPassed),
+			want: FlakeClassificationStableFail,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			entry := summarizeTestHistory("container", "test", tc.runs)
+			if entry.Classification != tc.want {
+				t.Fatalf("Classification = %q, want %q", entry.Classification, tc.want)
+			}
+			if tc.flake != 0 && entry.FlakeRate != tc.flake {
+				t.Fatalf("FlakeRate = %v, want %v", entry.FlakeRate, tc.flake)
+			}
+		})
+	}
+}
+
+func TestTestHistoryEntryRuns(t *testing.T) {
+	entry := TestHistoryEntry{Passed: 2, Failed: 1, Skipped: 1}
+	if got := entry.Runs(); got != 4 {
+		t.Fatalf("Runs() = %d, want 4", got)
+	}
+}
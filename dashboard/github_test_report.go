@@ -3,9 +3,7 @@ package main
 import (
 	"bufio"
 	"context"
-	"errors"
 	"fmt"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -14,7 +12,9 @@ import (
 	"regexp"
 
 	"github.com/google/go-github/v61/github"
-	"golang.org/x/oauth2"
+
+	"github.com/neurodesk/dashboard/internal/gh"
+	"github.com/neurodesk/dashboard/internal/triage"
 )
 
 // TestRunStatus represents the outcome of a container test from the GitHub report.
@@ -35,6 +35,12 @@ type TestRunEntry struct {
 	Details          string
 	Info             ContainerRunInfo
 	Tests            []ContainerTest
+
+	// Classification and Tags are populated by applyTriage from a
+	// -triage-script, used when a container's report has no per-test
+	// breakdown to annotate (len(Tests) == 0).
+	Classification string
+	Tags           []string
 }
 
 // ContainerRunInfo captures high-level metadata about a container test execution.
@@ -56,6 +62,16 @@ type ContainerTest struct {
 	Stdout     string
 	Stderr     string
 	Details    []string
+
+	// Classification and Tags are populated by applyTriage from a
+	// -triage-script, if one is configured.
+	Classification string
+	Tags           []string
+
+	// DurationMs is populated only when the test came from a structured
+	// "json neurocontainers-report" block; the Markdown scraper has no
+	// equivalent field to read it from.
+	DurationMs int
 }
 
 // TestRunSummary aggregates the headline numbers from the GitHub issue body.
@@ -77,11 +93,20 @@ type TestRunReport struct {
 	Skipped     []TestRunEntry
 	PassedCount int
 	Tested      []TestRunEntry
+
+	// FlakeHistory is populated by attachFlakeHistory with each test's
+	// pass/fail counts across every "Container test run" issue in the
+	// lookback window, so the dashboard can render badges like
+	// "flaky (3/12 runs failed)" next to Failures/Tested.
+	FlakeHistory *FlakeHistory
 }
 
-// loadLatestTestRunReport fetches and parses the most recent container test run issue for the configured repository.
-func loadLatestTestRunReport(ctx context.Context) (*TestRunReport, error) {
-	owner, repo := gitHubRepo()
+// loadLatestTestRunReport fetches and parses the most recent container test
+// run issue for the configured repository. If triageEngine is non-nil, each
+// entry (and its per-test breakdown, if any) is classified/tagged with it.
+// refresh bypasses the on-disk report cache.
+func loadLatestTestRunReport(ctx context.Context, triageEngine *triage.Engine, refresh bool) (*TestRunReport, error) {
+	owner, repo := gh.GitHubRepo()
 
 	client, err := newGitHubClient(ctx)
 	if err != nil {
@@ -98,12 +123,13 @@ func loadLatestTestRunReport(ctx context.Context) (*TestRunReport, error) {
 
 	summary := parseTestRunSummary(issue.GetBody())
 
-	comments, err := listAllIssueComments(ctx, client, owner, repo, issue.GetNumber())
+	comments, err := fetchIssueComments(ctx, client, owner, repo, issue, refresh)
 	if err != nil {
 		return nil, err
 	}
 
 	entries := parseTestRunEntries(comments)
+	applyTriage(triageEngine, entries)
 
 	var failures []TestRunEntry
 	var skipped []TestRunEntry
@@ -140,37 +166,21 @@ func loadLatestTestRunReport(ctx context.Context) (*TestRunReport, error) {
 		Tested:      tested,
 	}
 
+	attachFlakeHistory(ctx, report, refresh)
+
 	return report, nil
 }
 
+// gitHubRepo is kept as a thin alias for its callers elsewhere in this
+// package; it simply delegates to the shared gh package.
 func gitHubRepo() (owner string, repo string) {
-	const defaultRepo = "neurodesk/neurocontainers"
-
-	ownerRepo := strings.TrimSpace(os.Getenv("DASHBOARD_TEST_RUN_REPO"))
-	if ownerRepo == "" {
-		ownerRepo = defaultRepo
-	}
-
-	parts := strings.Split(ownerRepo, "/")
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		return "neurodesk", "neurocontainers"
-	}
-	return parts[0], parts[1]
+	return gh.GitHubRepo()
 }
 
+// newGitHubClient builds a github.Client honoring GITHUB_TOKEN plus, for
+// GitHub Enterprise installs, GITHUB_BASE_URL/GITHUB_UPLOAD_URL.
 func newGitHubClient(ctx context.Context) (*github.Client, error) {
-	if ctx == nil {
-		return nil, errors.New("context is required")
-	}
-
-	token := strings.TrimSpace(os.Getenv("GITHUB_TOKEN"))
-	if token == "" {
-		return github.NewClient(nil), nil
-	}
-
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(ctx, ts)
-	return github.NewClient(tc), nil
+	return gh.NewClient(ctx, gh.ClientOptions{})
 }
 
 func findLatestTestRunIssue(ctx context.Context, client *github.Client, owner, repo string) (*github.Issue, error) {
@@ -207,26 +217,6 @@ func findLatestTestRunIssue(ctx context.Context, client *github.Client, owner, r
 	return nil, nil
 }
 
-func listAllIssueComments(ctx context.Context, client *github.Client, owner, repo string, number int) ([]*github.IssueComment, error) {
-	opt := &github.IssueListCommentsOptions{
-		ListOptions: github.ListOptions{PerPage: 100},
-	}
-
-	var comments []*github.IssueComment
-	for {
-		batch, resp, err := client.Issues.ListComments(ctx, owner, repo, number, opt)
-		if err != nil {
-			return nil, fmt.Errorf("list comments: %w", err)
-		}
-		comments = append(comments, batch...)
-		if resp == nil || resp.NextPage == 0 {
-			break
-		}
-		opt.Page = resp.NextPage
-	}
-	return comments, nil
-}
-
 func parseTestRunSummary(body string) TestRunSummary {
 	summary := TestRunSummary{}
 
@@ -261,22 +251,43 @@ func parseTestRunSummary(body string) TestRunSummary {
 	return summary
 }
 
+// parseTestRunEntries parses every comment's structured JSON report blocks
+// and falls back to the Markdown scraper for anything a JSON block didn't
+// cover. Where both sources describe the same container, the JSON payload
+// wins on structured fields (Tests, Info) and Markdown only contributes the
+// human-written Details, via jsonSourced below.
 func parseTestRunEntries(comments []*github.IssueComment) map[string]TestRunEntry {
 	results := make(map[string]TestRunEntry)
+	jsonSourced := make(map[string]bool)
 
 	for _, comment := range comments {
 		body := comment.GetBody()
+
+		for _, entry := range parseJSONTestReportBlocks(body) {
+			current := results[entry.Container]
+			results[entry.Container] = mergeTestRunEntries(current, entry)
+			jsonSourced[entry.Container] = true
+		}
+
 		blocks := splitContainerBlocks(body)
 		for _, block := range blocks {
 			entry := parseContainerBlock(block)
 			if entry.Container == "" {
 				continue
 			}
+			if jsonSourced[entry.Container] {
+				fillMarkdownDetails(results, entry.Container, entry.Details)
+				continue
+			}
 			current := results[entry.Container]
 			results[entry.Container] = mergeTestRunEntries(current, entry)
 		}
 
 		for container, entry := range parseSimpleStatusLines(body) {
+			if jsonSourced[container] {
+				fillMarkdownDetails(results, container, entry.Details)
+				continue
+			}
 			current, exists := results[container]
 			if !exists || len(current.Tests) == 0 {
 				results[container] = mergeTestRunEntries(current, entry)
@@ -295,6 +306,20 @@ func parseTestRunEntries(comments []*github.IssueComment) map[string]TestRunEntr
 	return results
 }
 
+// fillMarkdownDetails fills in results[container].Details from the
+// Markdown parser's output, without touching any of the structured fields
+// a JSON report already populated for that container.
+func fillMarkdownDetails(results map[string]TestRunEntry, container, details string) {
+	if details == "" {
+		return
+	}
+	current := results[container]
+	if current.Details == "" {
+		current.Details = details
+		results[container] = current
+	}
+}
+
 func mergeTestRunEntries(base, update TestRunEntry) TestRunEntry {
 	if update.Container == "" {
 		return base
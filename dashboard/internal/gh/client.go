@@ -0,0 +1,331 @@
+// Package gh builds github.Client instances shared by the dashboard and its
+// companion CLI tools, with support for GitHub Enterprise and resilient
+// retry/rate-limit handling.
+package gh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v61/github"
+	"golang.org/x/oauth2"
+)
+
+// ClientOptions configures client construction so callers don't each
+// duplicate token/base-URL/transport wiring.
+type ClientOptions struct {
+	// SkipGitHub disables all GitHub access; NewClient returns nil, nil.
+	SkipGitHub bool
+
+	// Token is the GitHub access token. Defaults to the GITHUB_TOKEN env var.
+	Token string
+
+	// BaseURL and UploadURL target a GitHub Enterprise instance. Defaults to
+	// the GITHUB_BASE_URL / GITHUB_UPLOAD_URL env vars. When BaseURL is still
+	// unset, the public github.com API is used and UploadURL is ignored; an
+	// unset UploadURL defaults to BaseURL.
+	BaseURL   string
+	UploadURL string
+
+	// Transport, if set, is wrapped with the retry/rate-limit logic instead
+	// of http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+// NewClient builds a github.Client from opts, falling back to environment
+// variables for anything left unset. A retrying, rate-limit-aware transport
+// is always installed so callers don't need to handle 5xx/secondary rate
+// limits themselves.
+func NewClient(ctx context.Context, opts ClientOptions) (*github.Client, error) {
+	if opts.SkipGitHub {
+		return nil, nil
+	}
+	if ctx == nil {
+		return nil, errors.New("context is required")
+	}
+
+	token := strings.TrimSpace(opts.Token)
+	if token == "" {
+		token = strings.TrimSpace(os.Getenv("GITHUB_TOKEN"))
+	}
+
+	baseURL := strings.TrimSpace(opts.BaseURL)
+	if baseURL == "" {
+		baseURL = strings.TrimSpace(os.Getenv("GITHUB_BASE_URL"))
+	}
+	uploadURL := strings.TrimSpace(opts.UploadURL)
+	if uploadURL == "" {
+		uploadURL = strings.TrimSpace(os.Getenv("GITHUB_UPLOAD_URL"))
+	}
+
+	transport := opts.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	transport = &retryTransport{base: transport}
+
+	if token != "" {
+		transport = &oauth2.Transport{
+			Base:   transport,
+			Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}),
+		}
+	}
+
+	httpClient := &http.Client{Transport: transport}
+
+	if baseURL != "" {
+		if uploadURL == "" {
+			uploadURL = baseURL
+		}
+		return github.NewEnterpriseClient(baseURL, uploadURL, httpClient)
+	}
+
+	return github.NewClient(httpClient), nil
+}
+
+// retryTransport retries requests that fail with a 5xx status or a
+// secondary rate limit response, using exponential backoff and honoring
+// Retry-After. Primary rate limit responses (403 with X-RateLimit-Remaining
+// 0) block until the limit resets instead of failing the caller outright.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+}
+
+const defaultMaxRetries = 5
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	maxRetries := t.maxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultMaxRetries
+	}
+
+	var bodyBytes []byte
+	if req.Body != nil {
+		var err error
+		bodyBytes, err = readAndRestoreBody(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 && bodyBytes != nil {
+			req.Body = newBodyReader(bodyBytes)
+		}
+
+		resp, err = t.base.RoundTrip(req)
+		if err != nil {
+			if attempt == maxRetries {
+				return resp, err
+			}
+			sleep(req.Context(), backoffDelay(attempt))
+			continue
+		}
+
+		if wait, ok := primaryRateLimitWait(resp); ok {
+			if attempt == maxRetries {
+				return resp, err
+			}
+			drainAndClose(resp)
+			sleep(req.Context(), wait)
+			continue
+		}
+
+		if resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") != "0" && resp.Header.Get("Retry-After") != "" {
+			wait := retryAfter(resp)
+			if wait <= 0 {
+				wait = backoffDelay(attempt)
+			}
+			drainAndClose(resp)
+			if attempt == maxRetries {
+				return resp, err
+			}
+			sleep(req.Context(), wait)
+			continue
+		}
+
+		if resp.StatusCode >= 500 && attempt < maxRetries {
+			drainAndClose(resp)
+			sleep(req.Context(), backoffDelay(attempt))
+			continue
+		}
+
+		return resp, err
+	}
+
+	return resp, err
+}
+
+func primaryRateLimitWait(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden {
+		return 0, false
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return 0, false
+	}
+	resetHeader := resp.Header.Get("X-RateLimit-Reset")
+	resetUnix, err := strconv.ParseInt(resetHeader, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	wait := time.Until(time.Unix(resetUnix, 0))
+	if wait < 0 {
+		wait = time.Second
+	}
+	return wait, true
+}
+
+func retryAfter(resp *http.Response) time.Duration {
+	raw := resp.Header.Get("Retry-After")
+	if raw == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func backoffDelay(attempt int) time.Duration {
+	base := time.Duration(math.Pow(2, float64(attempt))) * 500 * time.Millisecond
+	jitter := time.Duration(rand.Int63n(int64(base) + 1))
+	return base + jitter
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	if ctx == nil {
+		time.Sleep(d)
+		return
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+func drainAndClose(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
+
+func readAndRestoreBody(req *http.Request) ([]byte, error) {
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = req.Body.Close()
+	req.Body = newBodyReader(data)
+	return data, nil
+}
+
+func newBodyReader(data []byte) io.ReadCloser {
+	return io.NopCloser(bytes.NewReader(data))
+}
+
+// GitHubRepo resolves owner/repo from the DASHBOARD_TEST_RUN_REPO env var
+// shared by the dashboard and its CLI companions, falling back to the
+// upstream neurocontainers repository.
+func GitHubRepo() (owner, repo string) {
+	const defaultRepo = "neurodesk/neurocontainers"
+
+	ownerRepo := strings.TrimSpace(os.Getenv("DASHBOARD_TEST_RUN_REPO"))
+	if ownerRepo == "" {
+		ownerRepo = defaultRepo
+	}
+
+	parts := strings.Split(ownerRepo, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "neurodesk", "neurocontainers"
+	}
+	return parts[0], parts[1]
+}
+
+// ListAllIssueComments pages through every comment on an issue.
+func ListAllIssueComments(ctx context.Context, client *github.Client, owner, repo string, number int) ([]*github.IssueComment, error) {
+	opt := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var comments []*github.IssueComment
+	for {
+		batch, resp, err := client.Issues.ListComments(ctx, owner, repo, number, opt)
+		if err != nil {
+			return nil, fmt.Errorf("list comments: %w", err)
+		}
+		comments = append(comments, batch...)
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return comments, nil
+}
+
+// FetchIssueCommentsConditional is ListAllIssueComments with an added
+// If-None-Match header on the first page, for callers (report_cache.go)
+// that can cache comments across calls and want GitHub's free 304 instead
+// of re-fetching and re-counting every page against rate limit when
+// nothing changed. It goes around the Issues.ListComments helper (which
+// doesn't expose the underlying *http.Response) to build the request and
+// read the ETag header directly.
+//
+// notModified reports whether etag is still current, in which case
+// comments is nil and the caller should keep using its previously cached
+// comments. newETag is the value to persist for the next call either way.
+func FetchIssueCommentsConditional(ctx context.Context, client *github.Client, owner, repo string, number int, etag string) (comments []*github.IssueComment, newETag string, notModified bool, err error) {
+	page := 1
+	for {
+		req, err := client.NewRequest(http.MethodGet, issueCommentsURL(owner, repo, number, page), nil)
+		if err != nil {
+			return nil, "", false, fmt.Errorf("build list comments request: %w", err)
+		}
+		if page == 1 && etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+
+		var batch []*github.IssueComment
+		resp, err := client.Do(ctx, req, &batch)
+		if resp != nil && resp.StatusCode == http.StatusNotModified {
+			return nil, resp.Header.Get("ETag"), true, nil
+		}
+		if err != nil {
+			return nil, "", false, fmt.Errorf("list comments (conditional): %w", err)
+		}
+		comments = append(comments, batch...)
+		if page == 1 {
+			newETag = resp.Header.Get("ETag")
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+	return comments, newETag, false, nil
+}
+
+func issueCommentsURL(owner, repo string, number, page int) string {
+	v := url.Values{}
+	v.Set("per_page", "100")
+	v.Set("page", strconv.Itoa(page))
+	return fmt.Sprintf("repos/%s/%s/issues/%d/comments?%s", owner, repo, number, v.Encode())
+}
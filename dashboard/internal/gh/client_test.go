@@ -0,0 +1,217 @@
+package gh
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v61/github"
+)
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func rateLimitedResponse(req *http.Request, reset time.Time) *http.Response {
+	header := http.Header{}
+	header.Set("X-RateLimit-Remaining", "0")
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	return &http.Response{
+		StatusCode: http.StatusForbidden,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(`{"message":"API rate limit exceeded"}`)),
+		Request:    req,
+	}
+}
+
+func TestRetryTransportRetriesUntilRateLimitClears(t *testing.T) {
+	var calls int
+	reset := time.Now().Add(10 * time.Millisecond)
+	transport := &retryTransport{
+		maxRetries: 5,
+		base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			if calls <= 2 {
+				return rateLimitedResponse(req, reset), nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{}`)),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.github.com/rate_limit", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3 (two rate-limited, one success)", calls)
+	}
+}
+
+// TestRetryTransportReturnsParsableRateLimitErrorOnExhaustion exercises
+// sustained primary rate-limiting that never clears: once maxRetries is hit,
+// the response must still be readable by go-github's CheckResponse (i.e. not
+// already drained and closed), so callers see a *github.RateLimitError
+// instead of a body-already-closed error.
+func TestRetryTransportReturnsParsableRateLimitErrorOnExhaustion(t *testing.T) {
+	const maxRetries = 3
+	var calls int
+	transport := &retryTransport{
+		maxRetries: maxRetries,
+		base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			// Every attempt comes back still rate-limited, with the reset
+			// time a few milliseconds out so the test doesn't actually wait
+			// for a real rate-limit window to pass.
+			return rateLimitedResponse(req, time.Now().Add(5*time.Millisecond)), nil
+		}),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.github.com/rate_limit", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip returned an error instead of the exhausted response: %v", err)
+	}
+	if calls != maxRetries+1 {
+		t.Errorf("calls = %d, want %d (initial attempt + maxRetries retries)", calls, maxRetries+1)
+	}
+
+	ghErr := github.CheckResponse(resp)
+	if _, ok := ghErr.(*github.RateLimitError); !ok {
+		t.Fatalf("CheckResponse(resp) = %v (%T), want a *github.RateLimitError", ghErr, ghErr)
+	}
+}
+
+func TestRetryTransportRetries5xx(t *testing.T) {
+	var calls int
+	transport := &retryTransport{
+		maxRetries: 3,
+		base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				return &http.Response{
+					StatusCode: http.StatusInternalServerError,
+					Body:       io.NopCloser(strings.NewReader("boom")),
+					Request:    req,
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{}`)),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.github.com/x", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestPrimaryRateLimitWait(t *testing.T) {
+	reset := time.Now().Add(time.Minute)
+	resp := rateLimitedResponse(httptest.NewRequest(http.MethodGet, "https://api.github.com/x", nil), reset)
+
+	wait, ok := primaryRateLimitWait(resp)
+	if !ok {
+		t.Fatal("primaryRateLimitWait returned ok=false for a rate-limited response")
+	}
+	if wait <= 0 || wait > time.Minute {
+		t.Errorf("wait = %v, want roughly a minute", wait)
+	}
+
+	resp.Header.Set("X-RateLimit-Remaining", "10")
+	if _, ok := primaryRateLimitWait(resp); ok {
+		t.Error("primaryRateLimitWait returned ok=true with remaining quota left")
+	}
+}
+
+func newTestGitHubClient(t *testing.T, handler http.HandlerFunc) *github.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(nil)
+	base, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatalf("parse test server URL: %v", err)
+	}
+	client.BaseURL = base
+	return client
+}
+
+func TestFetchIssueCommentsConditionalFetchesOnFirstCall(t *testing.T) {
+	var gotIfNoneMatch string
+	client := newTestGitHubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotIfNoneMatch = r.Header.Get("If-None-Match")
+		w.Header().Set("ETag", `"abc123"`)
+		w.Write([]byte(`[{"id":1},{"id":2}]`))
+	})
+
+	comments, etag, notModified, err := FetchIssueCommentsConditional(context.Background(), client, "neurodesk", "neurocontainers", 42, "")
+	if err != nil {
+		t.Fatalf("FetchIssueCommentsConditional: %v", err)
+	}
+	if notModified {
+		t.Error("notModified = true on an empty etag, want false")
+	}
+	if gotIfNoneMatch != "" {
+		t.Errorf("If-None-Match = %q sent with no prior etag, want none", gotIfNoneMatch)
+	}
+	if len(comments) != 2 {
+		t.Errorf("len(comments) = %d, want 2", len(comments))
+	}
+	if etag != `"abc123"` {
+		t.Errorf("etag = %q, want %q", etag, `"abc123"`)
+	}
+}
+
+func TestFetchIssueCommentsConditionalNotModified(t *testing.T) {
+	var gotIfNoneMatch string
+	client := newTestGitHubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotIfNoneMatch = r.Header.Get("If-None-Match")
+		w.Header().Set("ETag", `"abc123"`)
+		w.WriteHeader(http.StatusNotModified)
+	})
+
+	comments, etag, notModified, err := FetchIssueCommentsConditional(context.Background(), client, "neurodesk", "neurocontainers", 42, `"abc123"`)
+	if err != nil {
+		t.Fatalf("FetchIssueCommentsConditional: %v", err)
+	}
+	if !notModified {
+		t.Fatal("notModified = false, want true on a 304")
+	}
+	if comments != nil {
+		t.Errorf("comments = %v, want nil on a 304 (caller keeps its cached copy)", comments)
+	}
+	if gotIfNoneMatch != `"abc123"` {
+		t.Errorf("If-None-Match = %q, want the etag passed in", gotIfNoneMatch)
+	}
+	if etag != `"abc123"` {
+		t.Errorf("etag = %q, want %q", etag, `"abc123"`)
+	}
+}
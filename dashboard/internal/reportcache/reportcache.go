@@ -0,0 +1,135 @@
+// Package reportcache is a small on-disk JSON cache for GitHub issue and
+// comment fetches, so repeated dashboard builds against the same
+// "Container test run" issues don't re-page through the API every time.
+// Entries are keyed by (owner, repo, issue number) and are considered
+// usable as-is when both the issue's updated_at hasn't moved and the
+// entry is still within its TTL. Once either goes stale, the caller
+// (fetchIssueComments) revalidates with a conditional request using the
+// entry's ETag rather than always re-fetching, so a cache miss past TTL
+// still costs nothing against rate limit when the comments haven't
+// actually changed.
+package reportcache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v61/github"
+)
+
+// DefaultTTL bounds how long a cached entry is trusted while the issue's
+// updated_at is unchanged before its comments are revalidated with GitHub,
+// so a long-running dashboard process (e.g.
+// under `serve`) doesn't serve arbitrarily stale data between rebuilds.
+const DefaultTTL = 10 * time.Minute
+
+// Entry is everything cached for a single issue.
+type Entry struct {
+	UpdatedAt time.Time
+	CachedAt  time.Time
+	// ETag is the comments list's ETag as of Comments, so a revalidation
+	// past TTL (or after updated_at moves) can be a conditional request
+	// instead of an unconditional re-fetch.
+	ETag     string
+	Issue    *github.Issue
+	Comments []*github.IssueComment
+}
+
+// Fresh reports whether e was cached within ttl of now.
+func (e *Entry) Fresh(ttl time.Duration) bool {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return time.Since(e.CachedAt) < ttl
+}
+
+// ReportStore caches Entry values keyed by (owner, repo, issue number). It
+// exists so callers (fetchIssueComments) depend on an interface rather than
+// the on-disk JSONStore directly, leaving room for a different backend
+// (e.g. a single BoltDB file instead of one JSON file per issue) without
+// touching callers.
+type ReportStore interface {
+	Get(owner, repo string, issueNumber int) (*Entry, bool)
+	Put(owner, repo string, issueNumber int, entry *Entry) error
+
+	// TTL is how long a cached Entry is trusted while the issue's
+	// updated_at is unchanged before it is revalidated; see Entry.Fresh.
+	TTL() time.Duration
+}
+
+// JSONStore is the default ReportStore: a directory of one JSON file per
+// cached issue.
+//
+// A BoltDB-backed store was also on the table for this default (a single
+// file instead of one-per-issue), but swapping the storage engine is an
+// orthogonal decision from the conditional-request support above, and
+// one-file-per-issue has been perfectly serviceable at the dashboard's
+// current issue counts. Left for a follow-up if the file count itself
+// becomes a problem; the ReportStore interface above is exactly the seam
+// a BoltStore would plug into without touching callers.
+type JSONStore struct {
+	Dir string
+	ttl time.Duration
+}
+
+var _ ReportStore = (*JSONStore)(nil)
+
+// TTL returns how long a cached entry is trusted without revalidating.
+func (s *JSONStore) TTL() time.Duration { return s.ttl }
+
+// DefaultDir returns ~/.cache/neurocontainers-dashboard, falling back to a
+// tmp directory if the home directory can't be resolved.
+func DefaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "neurocontainers-dashboard")
+	}
+	return filepath.Join(home, ".cache", "neurocontainers-dashboard")
+}
+
+// NewStore creates dir if needed and returns a JSONStore rooted there.
+func NewStore(dir string, ttl time.Duration) (*JSONStore, error) {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create report cache dir: %w", err)
+	}
+	return &JSONStore{Dir: dir, ttl: ttl}, nil
+}
+
+// Get returns the cached entry for (owner, repo, issueNumber), if any.
+// Callers are responsible for checking Fresh and UpdatedAt before trusting
+// it in place of a live fetch.
+func (s *JSONStore) Get(owner, repo string, issueNumber int) (*Entry, bool) {
+	data, err := os.ReadFile(s.path(owner, repo, issueNumber))
+	if err != nil {
+		return nil, false
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, false
+	}
+	return &e, true
+}
+
+// Put writes entry to disk, overwriting any previous cache for this issue.
+func (s *JSONStore) Put(owner, repo string, issueNumber int, entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path(owner, repo, issueNumber), data, 0o644)
+}
+
+func (s *JSONStore) path(owner, repo string, issueNumber int) string {
+	return filepath.Join(s.Dir, fmt.Sprintf("%s_%s_%d.json", sanitize(owner), sanitize(repo), issueNumber))
+}
+
+func sanitize(s string) string {
+	return strings.ReplaceAll(s, "/", "_")
+}
@@ -0,0 +1,66 @@
+package reportcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v61/github"
+)
+
+func TestJSONStoreGetPutRoundTrip(t *testing.T) {
+	store, err := NewStore(t.TempDir(), time.Minute)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+
+	if _, ok := store.Get("neurodesk", "neurocontainers", 42); ok {
+		t.Fatal("Get on an empty store returned ok=true")
+	}
+
+	want := &Entry{
+		UpdatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		CachedAt:  time.Date(2026, 1, 2, 3, 5, 0, 0, time.UTC),
+		Issue:     &github.Issue{Number: github.Int(42)},
+		Comments:  []*github.IssueComment{{ID: github.Int64(1)}},
+	}
+	if err := store.Put("neurodesk", "neurocontainers", 42, want); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, ok := store.Get("neurodesk", "neurocontainers", 42)
+	if !ok {
+		t.Fatal("Get after Put returned ok=false")
+	}
+	if !got.UpdatedAt.Equal(want.UpdatedAt) || got.Issue.GetNumber() != 42 || len(got.Comments) != 1 {
+		t.Errorf("Get = %+v, want %+v", got, want)
+	}
+
+	if _, ok := store.Get("neurodesk", "neurocontainers", 43); ok {
+		t.Error("Get on an unrelated issue number returned ok=true")
+	}
+}
+
+func TestJSONStoreImplementsReportStore(t *testing.T) {
+	var _ ReportStore = (*JSONStore)(nil)
+}
+
+func TestEntryFresh(t *testing.T) {
+	cases := []struct {
+		name   string
+		cached time.Duration // how long ago CachedAt was
+		ttl    time.Duration
+		want   bool
+	}{
+		{name: "well within ttl", cached: time.Second, ttl: time.Minute, want: true},
+		{name: "past ttl", cached: 2 * time.Minute, ttl: time.Minute, want: false},
+		{name: "zero ttl falls back to DefaultTTL", cached: time.Second, ttl: 0, want: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := &Entry{CachedAt: time.Now().Add(-tc.cached)}
+			if got := e.Fresh(tc.ttl); got != tc.want {
+				t.Errorf("Fresh(%v) with CachedAt %v ago = %v, want %v", tc.ttl, tc.cached, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,538 @@
+// Package triage implements a small rule engine, modeled on
+// golang.org/x/build/cmd/watchflakes' internal/script, for classifying and
+// tagging container test failures.
+//
+// Rules are loaded one per line from a text file:
+//
+//	container ~ "^amico" && test.name ~ "smoke" => classify flaky
+//	stderr ~ "CUDA" => tag "gpu"
+//	return_code == 137 => classify infra
+//	status == failed && !(container ~ "^neuro") => ignore
+//
+// Conditions on the left of => compare the fields container, test.name,
+// status, stdout, stderr, and return_code
+// (~ for regexp, == and != for equality) combined with && || !, optionally
+// grouped with parens. Actions on the right of => are comma-separated
+// classify NAME, tag "NAME", or ignore.
+package triage
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Fields are the values one Rule is evaluated against - either a single
+// ContainerTest, or, when a container's report has no per-test breakdown,
+// the container-level TestRunEntry.
+type Fields struct {
+	Container  string
+	TestName   string
+	Status     string // "passed", "failed", "skipped", or "unknown"
+	Stdout     string
+	Stderr     string
+	ReturnCode *int
+}
+
+var knownFields = map[string]bool{
+	"container":   true,
+	"test.name":   true,
+	"status":      true,
+	"stdout":      true,
+	"stderr":      true,
+	"return_code": true,
+}
+
+// Rule is one parsed line: a condition and the actions to apply when it matches.
+type Rule struct {
+	// Source is the rule as written, and Line its 1-based line number in
+	// the script file - both are surfaced by dry-run tooling so a rule
+	// author can see exactly which line fired for a given failure.
+	Source string
+	Line   int
+
+	cond    cond
+	actions []action
+}
+
+// Matches reports whether the rule's condition holds for f.
+func (r *Rule) Matches(f Fields) bool {
+	return r.cond.eval(f)
+}
+
+// Verdict is the result of evaluating every rule in an Engine against one Fields.
+type Verdict struct {
+	Classification string
+	Tags           []string
+	Ignore         bool
+
+	// MatchedRules lists, in file order, every rule whose condition held -
+	// useful for a dry-run that shows which rule is responsible for a
+	// classification/tag.
+	MatchedRules []*Rule
+}
+
+// Engine is an ordered set of rules loaded from a triage script.
+type Engine struct {
+	Rules []*Rule
+}
+
+// Evaluate runs every rule against f. The first matching rule with a
+// `classify` action sets Classification; any matching `ignore` sets Ignore
+// without stopping later rules; every matching rule's `tag` actions are
+// collected.
+func (e *Engine) Evaluate(f Fields) Verdict {
+	var v Verdict
+	for _, r := range e.Rules {
+		if !r.Matches(f) {
+			continue
+		}
+		v.MatchedRules = append(v.MatchedRules, r)
+		for _, a := range r.actions {
+			switch act := a.(type) {
+			case classifyAction:
+				if v.Classification == "" {
+					v.Classification = act.name
+				}
+			case tagAction:
+				if !containsString(v.Tags, act.name) {
+					v.Tags = append(v.Tags, act.name)
+				}
+			case ignoreAction:
+				v.Ignore = true
+			}
+		}
+	}
+	return v
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseFile loads and parses a triage script from path.
+func ParseFile(path string) (*Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading triage script %q: %w", path, err)
+	}
+	engine, err := Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing triage script %q: %w", path, err)
+	}
+	return engine, nil
+}
+
+// Parse parses a triage script, one rule per non-blank, non-comment line.
+func Parse(text string) (*Engine, error) {
+	var rules []*Rule
+	for i, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule, err := parseRuleLine(line, i+1)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return &Engine{Rules: rules}, nil
+}
+
+func parseRuleLine(line string, lineNo int) (*Rule, error) {
+	toks, err := tokenize(line)
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %w", lineNo, err)
+	}
+
+	p := &parser{toks: toks}
+	c, err := p.parseExpr()
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %w", lineNo, err)
+	}
+	if err := p.expectOp("=>"); err != nil {
+		return nil, fmt.Errorf("line %d: %w", lineNo, err)
+	}
+	actions, err := p.parseActions()
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %w", lineNo, err)
+	}
+	if !p.atEOF() {
+		return nil, fmt.Errorf("line %d: unexpected trailing input at %q", lineNo, p.peek().text)
+	}
+
+	return &Rule{Source: line, Line: lineNo, cond: c, actions: actions}, nil
+}
+
+// --- conditions ---
+
+type cond interface {
+	eval(Fields) bool
+}
+
+type fieldCond struct {
+	field string
+	op    string // "~", "==", "!="
+	str   string
+	num   int
+	re    *regexp.Regexp
+}
+
+func (c fieldCond) eval(f Fields) bool {
+	if c.field == "return_code" {
+		got := f.ReturnCode
+		switch c.op {
+		case "==":
+			return got != nil && *got == c.num
+		case "!=":
+			return got == nil || *got != c.num
+		default:
+			return false
+		}
+	}
+
+	var got string
+	switch c.field {
+	case "container":
+		got = f.Container
+	case "test.name":
+		got = f.TestName
+	case "status":
+		got = f.Status
+	case "stdout":
+		got = f.Stdout
+	case "stderr":
+		got = f.Stderr
+	}
+
+	switch c.op {
+	case "~":
+		return c.re.MatchString(got)
+	case "==":
+		return got == c.str
+	case "!=":
+		return got != c.str
+	default:
+		return false
+	}
+}
+
+type andCond struct{ left, right cond }
+
+func (c andCond) eval(f Fields) bool { return c.left.eval(f) && c.right.eval(f) }
+
+type orCond struct{ left, right cond }
+
+func (c orCond) eval(f Fields) bool { return c.left.eval(f) || c.right.eval(f) }
+
+type notCond struct{ inner cond }
+
+func (c notCond) eval(f Fields) bool { return !c.inner.eval(f) }
+
+// --- actions ---
+
+type action interface {
+	isAction()
+}
+
+type classifyAction struct{ name string }
+
+func (classifyAction) isAction() {}
+
+type tagAction struct{ name string }
+
+func (tagAction) isAction() {}
+
+type ignoreAction struct{}
+
+func (ignoreAction) isAction() {}
+
+// --- tokenizer ---
+
+type tokenKind int
+
+const (
+	tEOF tokenKind = iota
+	tIdent
+	tString
+	tNumber
+	tOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(line string) ([]token, error) {
+	var toks []token
+	n := len(line)
+	i := 0
+	for i < n {
+		c := line[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '#':
+			i = n
+		case c == '"':
+			j := i + 1
+			var sb strings.Builder
+			for j < n && line[j] != '"' {
+				if line[j] == '\\' && j+1 < n {
+					j++
+				}
+				sb.WriteByte(line[j])
+				j++
+			}
+			if j >= n {
+				return nil, fmt.Errorf("unterminated string literal")
+			}
+			toks = append(toks, token{tString, sb.String()})
+			i = j + 1
+		case c == '(' || c == ')' || c == ',' || c == '~':
+			toks = append(toks, token{tOp, string(c)})
+			i++
+		case c == '!':
+			if i+1 < n && line[i+1] == '=' {
+				toks = append(toks, token{tOp, "!="})
+				i += 2
+			} else {
+				toks = append(toks, token{tOp, "!"})
+				i++
+			}
+		case c == '=':
+			if i+1 < n && line[i+1] == '=' {
+				toks = append(toks, token{tOp, "=="})
+				i += 2
+			} else if i+1 < n && line[i+1] == '>' {
+				toks = append(toks, token{tOp, "=>"})
+				i += 2
+			} else {
+				return nil, fmt.Errorf("unexpected '='")
+			}
+		case c == '&':
+			if i+1 < n && line[i+1] == '&' {
+				toks = append(toks, token{tOp, "&&"})
+				i += 2
+			} else {
+				return nil, fmt.Errorf("unexpected '&'")
+			}
+		case c == '|':
+			if i+1 < n && line[i+1] == '|' {
+				toks = append(toks, token{tOp, "||"})
+				i += 2
+			} else {
+				return nil, fmt.Errorf("unexpected '|'")
+			}
+		case isDigit(c):
+			j := i + 1
+			for j < n && isDigit(line[j]) {
+				j++
+			}
+			toks = append(toks, token{tNumber, line[i:j]})
+			i = j
+		case isIdentStart(c):
+			j := i + 1
+			for j < n && isIdentPart(line[j]) {
+				j++
+			}
+			toks = append(toks, token{tIdent, line[i:j]})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", string(c))
+		}
+	}
+	toks = append(toks, token{tEOF, ""})
+	return toks, nil
+}
+
+func isDigit(c byte) bool      { return c >= '0' && c <= '9' }
+func isIdentStart(c byte) bool { return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+func isIdentPart(c byte) bool  { return isIdentStart(c) || isDigit(c) || c == '.' }
+
+// --- recursive-descent parser ---
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if p.pos < len(p.toks)-1 {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) atEOF() bool {
+	return p.peek().kind == tEOF
+}
+
+func (p *parser) peekOp(op string) bool {
+	t := p.peek()
+	return t.kind == tOp && t.text == op
+}
+
+func (p *parser) expectOp(op string) error {
+	if !p.peekOp(op) {
+		return fmt.Errorf("expected %q, got %q", op, p.peek().text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) parseExpr() (cond, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (cond, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOp("||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orCond{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (cond, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOp("&&") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andCond{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (cond, error) {
+	if p.peekOp("!") {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notCond{inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (cond, error) {
+	if p.peekOp("(") {
+		p.next()
+		inner, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectOp(")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (cond, error) {
+	fieldTok := p.next()
+	if fieldTok.kind != tIdent {
+		return nil, fmt.Errorf("expected field name, got %q", fieldTok.text)
+	}
+	if !knownFields[fieldTok.text] {
+		return nil, fmt.Errorf("unknown field %q", fieldTok.text)
+	}
+
+	opTok := p.next()
+	if opTok.kind != tOp || (opTok.text != "~" && opTok.text != "==" && opTok.text != "!=") {
+		return nil, fmt.Errorf("expected ~, ==, or !=, got %q", opTok.text)
+	}
+
+	valTok := p.next()
+	switch valTok.kind {
+	case tString, tIdent, tNumber:
+		// str always holds the literal's text, even for tNumber, so that
+		// non-return_code fields (which compare as strings) see the value
+		// the author wrote rather than an empty string.
+		c := fieldCond{field: fieldTok.text, op: opTok.text, str: valTok.text}
+		if valTok.kind == tNumber {
+			n, err := strconv.Atoi(valTok.text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", valTok.text)
+			}
+			c.num = n
+		}
+		if opTok.text == "~" {
+			if fieldTok.text == "return_code" {
+				return nil, fmt.Errorf("~ is not supported for return_code; use == or !=")
+			}
+			re, err := regexp.Compile(valTok.text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regexp %q: %w", valTok.text, err)
+			}
+			c.re = re
+		}
+		return c, nil
+	default:
+		return nil, fmt.Errorf("expected a comparison value, got %q", valTok.text)
+	}
+}
+
+func (p *parser) parseActions() ([]action, error) {
+	var actions []action
+	for {
+		tok := p.next()
+		if tok.kind != tIdent {
+			return nil, fmt.Errorf("expected an action, got %q", tok.text)
+		}
+		switch tok.text {
+		case "classify":
+			nameTok := p.next()
+			if nameTok.kind != tIdent && nameTok.kind != tString {
+				return nil, fmt.Errorf("expected a classification name after classify")
+			}
+			actions = append(actions, classifyAction{name: nameTok.text})
+		case "tag":
+			nameTok := p.next()
+			if nameTok.kind != tString && nameTok.kind != tIdent {
+				return nil, fmt.Errorf("expected a tag name after tag")
+			}
+			actions = append(actions, tagAction{name: nameTok.text})
+		case "ignore":
+			actions = append(actions, ignoreAction{})
+		default:
+			return nil, fmt.Errorf("unknown action %q", tok.text)
+		}
+
+		if p.peekOp(",") {
+			p.next()
+			continue
+		}
+		break
+	}
+	return actions, nil
+}
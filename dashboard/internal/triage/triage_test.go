@@ -0,0 +1,134 @@
+package triage
+
+import "testing"
+
+func intPtr(n int) *int { return &n }
+
+func TestFieldCondEval(t *testing.T) {
+	cases := []struct {
+		name string
+		rule string
+		f    Fields
+		want bool
+	}{
+		{
+			name: "string equality match",
+			rule: `container == "amico"`,
+			f:    Fields{Container: "amico"},
+			want: true,
+		},
+		{
+			name: "string equality mismatch",
+			rule: `container == "amico"`,
+			f:    Fields{Container: "other"},
+			want: false,
+		},
+		{
+			name: "string inequality",
+			rule: `status != "passed"`,
+			f:    Fields{Status: "failed"},
+			want: true,
+		},
+		{
+			name: "regexp match",
+			rule: `test.name ~ "^Test"`,
+			f:    Fields{TestName: "TestFoo"},
+			want: true,
+		},
+		{
+			name: "regexp no match",
+			rule: `test.name ~ "^Test"`,
+			f:    Fields{TestName: "FooTest"},
+			want: false,
+		},
+		{
+			name: "return_code equality",
+			rule: `return_code == 137`,
+			f:    Fields{ReturnCode: intPtr(137)},
+			want: true,
+		},
+		{
+			name: "return_code inequality with nil",
+			rule: `return_code != 137`,
+			f:    Fields{ReturnCode: nil},
+			want: true,
+		},
+		{
+			name: "return_code equality with nil never matches",
+			rule: `return_code == 137`,
+			f:    Fields{ReturnCode: nil},
+			want: false,
+		},
+		{
+			name: "bare number against a string field compares as text, not empty string",
+			rule: `container == 404`,
+			f:    Fields{Container: "404"},
+			want: true,
+		},
+		{
+			name: "bare number against a string field does not match an empty field",
+			rule: `container == 404`,
+			f:    Fields{Container: ""},
+			want: false,
+		},
+		{
+			name: "bare number with regexp op",
+			rule: `stderr ~ 500`,
+			f:    Fields{Stderr: "HTTP 500 error"},
+			want: true,
+		},
+		{
+			name: "conjunction",
+			rule: `container ~ "^amico" && status == "failed"`,
+			f:    Fields{Container: "amico-dev", Status: "failed"},
+			want: true,
+		},
+		{
+			name: "negation",
+			rule: `!(container ~ "^neuro")`,
+			f:    Fields{Container: "amico"},
+			want: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			engine, err := Parse(tc.rule + " => ignore")
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tc.rule, err)
+			}
+			if len(engine.Rules) != 1 {
+				t.Fatalf("Parse(%q) = %d rules, want 1", tc.rule, len(engine.Rules))
+			}
+			got := engine.Rules[0].Matches(tc.f)
+			if got != tc.want {
+				t.Errorf("rule %q against %+v = %v, want %v", tc.rule, tc.f, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseRejectsRegexpOnReturnCode(t *testing.T) {
+	_, err := Parse(`return_code ~ "13."  => ignore`)
+	if err == nil {
+		t.Fatal("Parse accepted return_code ~ pattern, want an error")
+	}
+}
+
+func TestEvaluateOrdering(t *testing.T) {
+	engine, err := Parse(`return_code == 137 => classify infra
+container ~ "^amico" => tag "amico", classify flaky`)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	v := engine.Evaluate(Fields{Container: "amico-core", ReturnCode: intPtr(137)})
+	if v.Classification != "infra" {
+		t.Errorf("Classification = %q, want %q (first matching classify wins)", v.Classification, "infra")
+	}
+	if len(v.Tags) != 1 || v.Tags[0] != "amico" {
+		t.Errorf("Tags = %v, want [amico]", v.Tags)
+	}
+	if len(v.MatchedRules) != 2 {
+		t.Errorf("MatchedRules = %d, want 2", len(v.MatchedRules))
+	}
+}
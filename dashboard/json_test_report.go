@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// jsonReportFenceInfo is the fenced-code-block info string identifying a
+// structured test report, e.g. a comment containing:
+//
+//	```json neurocontainers-report
+//	{"schema_version": 1, "container": "afni", ...}
+//	```
+const jsonReportFenceInfo = "json neurocontainers-report"
+
+// jsonTestReportSchemaVersion is the only schema_version parseJSONTestReport
+// understands. A future incompatible bump should fail loudly here rather
+// than silently misparse, the same way the Markdown scraper's fragility to
+// template changes was the problem this path exists to avoid.
+const jsonTestReportSchemaVersion = 1
+
+// jsonTestReport is the structured counterpart to parseContainerBlock's
+// Markdown scraping: a comment may embed one of these instead of (or in
+// addition to) the emoji/bold Markdown a human reads, so the dashboard
+// isn't coupled to exact formatting.
+//
+// Ingestion is limited to the fenced-code-block form above; a comment
+// linking out to an attached gist with the same content type is not
+// handled here and is out of scope for now.
+type jsonTestReport struct {
+	SchemaVersion int            `json:"schema_version"`
+	Container     string         `json:"container"`
+	ImagePath     string         `json:"image_path"`
+	Runtime       string         `json:"runtime"`
+	Tests         []jsonTestCase `json:"tests"`
+}
+
+type jsonTestCase struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	ReturnCode *int   `json:"return_code"`
+	Stdout     string `json:"stdout"`
+	Stderr     string `json:"stderr"`
+	DurationMs int    `json:"duration_ms"`
+}
+
+// extractJSONReportBlocks returns the contents of every fenced code block
+// in body whose info string is jsonReportFenceInfo.
+func extractJSONReportBlocks(body string) []string {
+	lines := strings.Split(body, "\n")
+
+	var blocks []string
+	var buf []string
+	inBlock := false
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !inBlock {
+			if strings.HasPrefix(trimmed, "```") && strings.TrimSpace(strings.TrimPrefix(trimmed, "```")) == jsonReportFenceInfo {
+				inBlock = true
+				buf = buf[:0]
+			}
+			continue
+		}
+		if trimmed == "```" {
+			blocks = append(blocks, strings.Join(buf, "\n"))
+			inBlock = false
+			continue
+		}
+		buf = append(buf, line)
+	}
+
+	return blocks
+}
+
+// parseJSONTestReport unmarshals raw into a TestRunEntry. Unlike the
+// Markdown parser, an invalid or unrecognized-schema payload is reported
+// back as an error rather than silently dropped, so a malformed report
+// shows up in logs instead of vanishing from the dashboard.
+func parseJSONTestReport(raw string) (TestRunEntry, error) {
+	var payload jsonTestReport
+	if err := json.Unmarshal([]byte(raw), &payload); err != nil {
+		return TestRunEntry{}, fmt.Errorf("unmarshal json test report: %w", err)
+	}
+	if payload.SchemaVersion != jsonTestReportSchemaVersion {
+		return TestRunEntry{}, fmt.Errorf("json test report: unsupported schema_version %d", payload.SchemaVersion)
+	}
+	if payload.Container == "" {
+		return TestRunEntry{}, fmt.Errorf("json test report: missing container")
+	}
+
+	entry := TestRunEntry{
+		Container: payload.Container,
+		Info: ContainerRunInfo{
+			ImagePath: payload.ImagePath,
+			Runtime:   payload.Runtime,
+		},
+	}
+
+	for _, t := range payload.Tests {
+		status := normalizeTestRunStatus(t.Status)
+		entry.Tests = append(entry.Tests, ContainerTest{
+			Name:       t.Name,
+			Status:     status,
+			ReturnCode: t.ReturnCode,
+			Stdout:     t.Stdout,
+			Stderr:     t.Stderr,
+			DurationMs: t.DurationMs,
+		})
+
+		entry.Info.TestsTotal++
+		switch status {
+		case TestRunStatusPassed:
+			entry.Info.TestsPassed++
+		case TestRunStatusFailed:
+			entry.Info.TestsFailed++
+		case TestRunStatusSkipped:
+			entry.Info.TestsSkipped++
+		}
+	}
+
+	entry.Status = overallTestRunStatus(entry.Info)
+	if entry.Info.TestsTotal > 0 {
+		entry.Details = fmt.Sprintf("Tests: %d/%d passed (failed %d, skipped %d)", entry.Info.TestsPassed, entry.Info.TestsTotal, entry.Info.TestsFailed, entry.Info.TestsSkipped)
+	}
+
+	return entry, nil
+}
+
+func normalizeTestRunStatus(s string) TestRunStatus {
+	switch TestRunStatus(strings.ToLower(strings.TrimSpace(s))) {
+	case TestRunStatusPassed:
+		return TestRunStatusPassed
+	case TestRunStatusFailed:
+		return TestRunStatusFailed
+	case TestRunStatusSkipped:
+		return TestRunStatusSkipped
+	default:
+		return TestRunStatusUnknown
+	}
+}
+
+// overallTestRunStatus derives a container's overall status from its
+// per-test counts, since the JSON schema has no separate top-level status
+// field for it to disagree with.
+func overallTestRunStatus(info ContainerRunInfo) TestRunStatus {
+	switch {
+	case info.TestsTotal == 0:
+		return TestRunStatusUnknown
+	case info.TestsFailed > 0:
+		return TestRunStatusFailed
+	case info.TestsPassed == 0:
+		return TestRunStatusSkipped
+	default:
+		return TestRunStatusPassed
+	}
+}
+
+// parseJSONTestReportBlocks extracts and parses every structured report in
+// body, logging and skipping (rather than failing the whole comment) any
+// block that doesn't parse.
+func parseJSONTestReportBlocks(body string) []TestRunEntry {
+	var entries []TestRunEntry
+	for _, raw := range extractJSONReportBlocks(body) {
+		entry, err := parseJSONTestReport(raw)
+		if err != nil {
+			slog.Warn("parse json test report block", "error", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v61/github"
+)
+
+func TestParseTestRunEntriesPrefersJSONReport(t *testing.T) {
+	body := "```json neurocontainers-report\n" +
+		`{"schema_version": 1, "container": "afni", "image_path": "/opt/afni", "runtime": "apptainer",` +
+		`"tests": [{"name": "smoke", "status": "passed", "return_code": 0, "duration_ms": 120}]}` +
+		"\n```\n" +
+		"✅ **afni** — looks good\n"
+
+	comments := []*github.IssueComment{{Body: &body}}
+	entries := parseTestRunEntries(comments)
+
+	entry, ok := entries["afni"]
+	if !ok {
+		t.Fatalf("expected afni entry, not found")
+	}
+	if entry.Status != TestRunStatusPassed {
+		t.Fatalf("Status = %q, want %q", entry.Status, TestRunStatusPassed)
+	}
+	if len(entry.Tests) != 1 || entry.Tests[0].Name != "smoke" {
+		t.Fatalf("Tests = %+v, want one test named smoke", entry.Tests)
+	}
+	if entry.Tests[0].DurationMs != 120 {
+		t.Fatalf("DurationMs = %d, want 120", entry.Tests[0].DurationMs)
+	}
+	if entry.Info.Runtime != "apptainer" {
+		t.Fatalf("Runtime = %q, want apptainer", entry.Info.Runtime)
+	}
+	if entry.Details == "" {
+		t.Fatalf("expected Details to be filled in from the JSON test counts")
+	}
+}
+
+func TestParseJSONTestReportRejectsUnknownSchema(t *testing.T) {
+	_, err := parseJSONTestReport(`{"schema_version": 99, "container": "afni"}`)
+	if err == nil {
+		t.Fatalf("expected an error for an unsupported schema_version")
+	}
+}
@@ -15,7 +15,11 @@ import (
 	"sort"
 	"strings"
 
+	"golang.org/x/mod/semver"
 	"gopkg.in/yaml.v3"
+
+	"github.com/neurodesk/dashboard/internal/triage"
+	"github.com/neurodesk/dashboard/reporter"
 )
 
 //go:embed templates/*.html
@@ -23,20 +27,32 @@ var tplFs embed.FS
 
 var templates = template.Must(template.ParseFS(tplFs, "templates/*.html"))
 
+// VersionInfo captures a single version string alongside its semver
+// canonicalization, used to compare versions that differ only cosmetically
+// (e.g. "1.0", "1.0.0", "v1.0.0").
+type VersionInfo struct {
+	Display    string // the original string, as found in releases/apps.json/build.yaml
+	Canonical  string // semver.Canonical form, empty if the version isn't semver-shaped
+	Prerelease bool
+	Build      string
+}
+
 type ContainerProgress struct {
-	Name            string
-	ReleaseVersions []string
-	AppVersions     []string
-	MatchedVersions []string
-	MissingVersions []string
-	ExtraVersions   []string
-	Warnings        []string
-	HasBuildYAML    bool
-	BuildVersion    string
-	BuildReleased   bool
-	TestStatus      TestRunStatus
-	TestDetails     string
-	TestRun         *TestRunEntry
+	Name               string
+	ReleaseVersions    []string
+	AppVersions        []string
+	MatchedVersions    []string
+	MissingVersions    []string
+	ExtraVersions      []string
+	ReleaseVersionInfo []VersionInfo
+	AppVersionInfo     []VersionInfo
+	Warnings           []string
+	HasBuildYAML       bool
+	BuildVersion       string
+	BuildReleased      bool
+	TestStatus         TestRunStatus
+	TestDetails        string
+	TestRun            *TestRunEntry
 }
 
 type indexData struct {
@@ -157,8 +173,8 @@ func loadContainerProgress(cfg DataSources) ([]ContainerProgress, error) {
 	}
 
 	type containerData struct {
-		releaseVersions map[string]struct{}
-		appVersions     map[string]struct{}
+		releaseVersions map[string]VersionInfo
+		appVersions     map[string]VersionInfo
 		hasBuildYAML    bool
 		hasBuildSH      bool
 		buildVersion    string
@@ -171,8 +187,8 @@ func loadContainerProgress(cfg DataSources) ([]ContainerProgress, error) {
 		c, ok := containers[name]
 		if !ok {
 			c = &containerData{
-				releaseVersions: map[string]struct{}{},
-				appVersions:     map[string]struct{}{},
+				releaseVersions: map[string]VersionInfo{},
+				appVersions:     map[string]VersionInfo{},
 			}
 			containers[name] = c
 		}
@@ -209,7 +225,7 @@ func loadContainerProgress(cfg DataSources) ([]ContainerProgress, error) {
 			if version == "" {
 				continue
 			}
-			container.releaseVersions[version] = struct{}{}
+			addVersion(container.releaseVersions, version)
 		}
 	}
 
@@ -255,7 +271,7 @@ func loadContainerProgress(cfg DataSources) ([]ContainerProgress, error) {
 			if version == "" {
 				continue
 			}
-			container.appVersions[version] = struct{}{}
+			addVersion(container.appVersions, version)
 		}
 	}
 
@@ -273,8 +289,8 @@ func loadContainerProgress(cfg DataSources) ([]ContainerProgress, error) {
 	for _, name := range containerNames {
 		data := containers[name]
 
-		releaseVersions := setToSortedSlice(data.releaseVersions)
-		appVersions := setToSortedSlice(data.appVersions)
+		releaseVersions, releaseInfo := versionSetToSorted(data.releaseVersions)
+		appVersions, appInfo := versionSetToSorted(data.appVersions)
 		var warnings []string
 		if !data.hasBuildYAML && data.hasBuildSH {
 			warnings = append(warnings, "Found build.sh but missing build.yaml")
@@ -286,9 +302,17 @@ func loadContainerProgress(cfg DataSources) ([]ContainerProgress, error) {
 		if data.buildVersionErr != "" {
 			warnings = append(warnings, fmt.Sprintf("Failed to parse build.yaml: %s", data.buildVersionErr))
 		} else if data.buildVersion != "" {
-			_, buildReleased = data.releaseVersions[data.buildVersion]
+			buildKey, _, _ := normalizeVersion(data.buildVersion)
+			if buildKey == "" {
+				buildKey = data.buildVersion
+			}
+			_, buildReleased = data.releaseVersions[buildKey]
 			if !buildReleased {
-				warnings = append(warnings, fmt.Sprintf("build.yaml version %s has no release", data.buildVersion))
+				if newer := newestRelease(data.releaseVersions); newer != "" && semver.Compare(buildKey, newer) < 0 && semver.IsValid(buildKey) && semver.IsValid(newer) {
+					warnings = append(warnings, fmt.Sprintf("build.yaml version %s is outdated; newer release %s exists", data.buildVersion, newer))
+				} else {
+					warnings = append(warnings, fmt.Sprintf("build.yaml version %s has no release (unreleased build.yaml)", data.buildVersion))
+				}
 			}
 		}
 
@@ -297,59 +321,133 @@ func loadContainerProgress(cfg DataSources) ([]ContainerProgress, error) {
 		extra := sortedDifference(data.appVersions, data.releaseVersions)
 
 		progress = append(progress, ContainerProgress{
-			Name:            name,
-			ReleaseVersions: releaseVersions,
-			AppVersions:     appVersions,
-			MatchedVersions: matched,
-			MissingVersions: missing,
-			ExtraVersions:   extra,
-			Warnings:        warnings,
-			HasBuildYAML:    data.hasBuildYAML,
-			BuildVersion:    data.buildVersion,
-			BuildReleased:   buildReleased,
-			TestStatus:      TestRunStatusUnknown,
+			Name:               name,
+			ReleaseVersions:    releaseVersions,
+			AppVersions:        appVersions,
+			MatchedVersions:    matched,
+			MissingVersions:    missing,
+			ExtraVersions:      extra,
+			ReleaseVersionInfo: releaseInfo,
+			AppVersionInfo:     appInfo,
+			Warnings:           warnings,
+			HasBuildYAML:       data.hasBuildYAML,
+			BuildVersion:       data.buildVersion,
+			BuildReleased:      buildReleased,
+			TestStatus:         TestRunStatusUnknown,
 		})
 	}
 
 	return progress, nil
 }
 
-func setToSortedSlice(set map[string]struct{}) []string {
+// normalizeVersion canonicalizes raw into a semver form suitable for use as a
+// set key. ok is false when raw isn't semver-shaped, in which case raw itself
+// should be used as the key so the version still participates in set
+// operations (just without semver-aware comparison).
+func normalizeVersion(raw string) (canonical string, display string, ok bool) {
+	display = raw
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return "", display, false
+	}
+	candidate := trimmed
+	if !strings.HasPrefix(candidate, "v") {
+		candidate = "v" + candidate
+	}
+	if !semver.IsValid(candidate) {
+		return "", display, false
+	}
+	return semver.Canonical(candidate), display, true
+}
+
+func addVersion(set map[string]VersionInfo, raw string) {
+	canonical, display, ok := normalizeVersion(raw)
+	key := raw
+	if ok {
+		key = canonical
+	}
+	if _, exists := set[key]; exists {
+		return
+	}
+	info := VersionInfo{Display: display}
+	if ok {
+		info.Canonical = canonical
+		info.Prerelease = semver.Prerelease(canonical) != ""
+		info.Build = semver.Build(canonical)
+	}
+	set[key] = info
+}
+
+// newestRelease returns the highest-precedence canonical key among releases,
+// ignoring entries that aren't semver-shaped.
+func newestRelease(set map[string]VersionInfo) string {
+	var newest string
+	for key, info := range set {
+		if info.Canonical == "" {
+			continue
+		}
+		if newest == "" || semver.Compare(key, newest) > 0 {
+			newest = key
+		}
+	}
+	return newest
+}
+
+// semverLess orders two version set keys by semver precedence when both are
+// canonical semver forms, falling back to a plain string comparison so
+// non-semver versions still sort deterministically.
+func semverLess(a, b string) bool {
+	if semver.IsValid(a) && semver.IsValid(b) {
+		if cmp := semver.Compare(a, b); cmp != 0 {
+			return cmp < 0
+		}
+	}
+	return a < b
+}
+
+func versionSetToSorted(set map[string]VersionInfo) ([]string, []VersionInfo) {
 	if len(set) == 0 {
-		return nil
+		return nil, nil
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
 	}
-	slice := make([]string, 0, len(set))
-	for v := range set {
-		slice = append(slice, v)
+	sort.Slice(keys, func(i, j int) bool { return semverLess(keys[i], keys[j]) })
+
+	display := make([]string, 0, len(keys))
+	infos := make([]VersionInfo, 0, len(keys))
+	for _, k := range keys {
+		display = append(display, set[k].Display)
+		infos = append(infos, set[k])
 	}
-	sort.Strings(slice)
-	return slice
+	return display, infos
 }
 
-func sortedIntersection(a map[string]struct{}, b map[string]struct{}) []string {
+func sortedIntersection(a map[string]VersionInfo, b map[string]VersionInfo) []string {
 	if len(a) == 0 || len(b) == 0 {
 		return nil
 	}
 	var result []string
-	for value := range a {
-		if _, ok := b[value]; ok {
-			result = append(result, value)
+	for key, info := range a {
+		if _, ok := b[key]; ok {
+			result = append(result, info.Display)
 		}
 	}
 	sort.Strings(result)
 	return result
 }
 
-func sortedDifference(source, other map[string]struct{}) []string {
+func sortedDifference(source, other map[string]VersionInfo) []string {
 	if len(source) == 0 {
 		return nil
 	}
 	var diff []string
-	for value := range source {
-		if _, ok := other[value]; ok {
+	for key, info := range source {
+		if _, ok := other[key]; ok {
 			continue
 		}
-		diff = append(diff, value)
+		diff = append(diff, info.Display)
 	}
 	if len(diff) == 0 {
 		return nil
@@ -451,8 +549,12 @@ func parseVersionFromAppKey(key string) string {
 }
 
 type BuildOptions struct {
-	SkipGitHub  bool
-	DataSources DataSources
+	SkipGitHub   bool
+	DataSources  DataSources
+	TriageScript string
+
+	// RefreshCache bypasses the on-disk GitHub issue/comment cache.
+	RefreshCache bool
 }
 
 func buildDashboardData(ctx context.Context, opts BuildOptions) (indexData, error) {
@@ -468,8 +570,18 @@ func buildDashboardData(ctx context.Context, opts BuildOptions) (indexData, erro
 
 	var testRun *TestRunReport
 	if !opts.SkipGitHub {
+		var triageEngine *triage.Engine
+		if opts.TriageScript != "" {
+			engine, err := triage.ParseFile(opts.TriageScript)
+			if err != nil {
+				slog.Warn("parse triage script", "path", opts.TriageScript, "error", err)
+			} else {
+				triageEngine = engine
+			}
+		}
+
 		var loadErr error
-		testRun, loadErr = loadLatestTestRunReport(ctx)
+		testRun, loadErr = loadLatestTestRunReport(ctx, triageEngine, opts.RefreshCache)
 		if loadErr != nil {
 			slog.Warn("load github test report", "error", loadErr)
 		}
@@ -573,23 +685,105 @@ func renderDashboard(ctx context.Context, outDir string, opts BuildOptions) (str
 		return "", err
 	}
 
+	if data.TestRun != nil {
+		syncTestRunHistories(ctx, data.TestRun)
+	}
+
+	var flakes *FlakeHistory
+	if data.TestRun != nil {
+		flakes = data.TestRun.FlakeHistory
+	}
+	for _, container := range data.Containers {
+		if err := renderContainerPage(outDir, container, flakes); err != nil {
+			slog.Warn("render container page", "container", container.Name, "error", err)
+		}
+	}
+
 	slog.Info("dashboard generated", "path", dest, "containers", len(data.Containers))
 	return dest, nil
 }
 
+// syncWarningsToGitHub loads the current container progress and syncs any
+// unresolved warnings to GitHub tracking issues. Without --apply it uses a
+// DryRunReporter so CI can produce a diff-style summary without mutating
+// anything. openDraftPRs is ignored unless apply is also set.
+func syncWarningsToGitHub(ctx context.Context, opts BuildOptions, apply, openDraftPRs bool) error {
+	if err := opts.DataSources.normalize(); err != nil {
+		return err
+	}
+	progress, err := loadContainerProgress(opts.DataSources)
+	if err != nil {
+		return err
+	}
+
+	containers := make([]reporter.Container, 0, len(progress))
+	for _, p := range progress {
+		containers = append(containers, reporter.Container{
+			Name:            p.Name,
+			Warnings:        p.Warnings,
+			BuildVersion:    p.BuildVersion,
+			HasBuildYAML:    p.HasBuildYAML,
+			ReleaseVersions: p.ReleaseVersions,
+		})
+	}
+
+	var rep reporter.Reporter
+	if apply {
+		client, err := newGitHubClient(ctx)
+		if err != nil {
+			return err
+		}
+		owner, repo := gitHubRepo()
+		rep = &reporter.GitHubReporter{Client: client, Owner: owner, Repo: repo, OpenDraftPRs: openDraftPRs}
+	} else {
+		rep = reporter.DryRunReporter{}
+	}
+
+	return rep.Sync(ctx, containers)
+}
+
 func main() {
 	if err := loadEnvFile(".env"); err != nil {
 		slog.Warn("load env file", "error", err)
 	}
 
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "serve" {
+		if err := runServe(args[1:]); err != nil {
+			slog.Error("serve", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(args) > 0 && args[0] == "triage-dry-run" {
+		if err := runTriageDryRun(args[1:]); err != nil {
+			slog.Error("triage-dry-run", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(args) > 0 && args[0] == "report" {
+		if err := runReport(args[1:]); err != nil {
+			slog.Error("report", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 	outDir := fs.String("out", "dist", "directory to write the generated site")
 	skipGitHub := fs.Bool("skip-github", false, "skip fetching the latest GitHub test run report")
 	releasesDir := fs.String("releases-dir", "", "path to container release metadata (defaults to releases/)")
 	recipesDir := fs.String("recipes-dir", "", "path to container recipes (defaults to recipes/)")
 	appsJSON := fs.String("apps-json", "", "path to apps.json for comparing published apps")
-
-	if err := fs.Parse(os.Args[1:]); err != nil {
+	openIssues := fs.Bool("open-issues", false, "sync container warnings to GitHub tracking issues (requires GITHUB_TOKEN)")
+	apply := fs.Bool("apply", false, "actually make GitHub API calls for --open-issues; otherwise print a dry-run summary")
+	openDraftPRs := fs.Bool("open-draft-prs", false, "with --open-issues --apply, also open a draft PR seeding a placeholder release for containers whose build.yaml is ahead of every release")
+	triageScript := fs.String("triage-script", os.Getenv("DASHBOARD_TRIAGE_SCRIPT"), "path to a triage rules file classifying container test failures")
+	post := fs.Bool("post", false, "post/update a triage summary comment on the test run issue (requires GITHUB_TOKEN with write scope)")
+	refresh := fs.Bool("refresh", false, "bypass the on-disk GitHub issue/comment cache")
+
+	if err := fs.Parse(args); err != nil {
 		slog.Error("parse flags", "error", err)
 		os.Exit(2)
 	}
@@ -603,10 +797,26 @@ func main() {
 			RecipesDir:   *recipesDir,
 			AppsJSONPath: *appsJSON,
 		},
+		TriageScript: *triageScript,
+		RefreshCache: *refresh,
 	}
 
 	if _, err := renderDashboard(ctx, *outDir, opts); err != nil {
 		slog.Error("generate dashboard", "error", err)
 		os.Exit(1)
 	}
+
+	if *openIssues {
+		if err := syncWarningsToGitHub(ctx, opts, *apply, *openDraftPRs); err != nil {
+			slog.Error("sync warnings to github", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	if *post {
+		if err := postTriageSummary(ctx, opts); err != nil {
+			slog.Error("post triage summary", "error", err)
+			os.Exit(1)
+		}
+	}
 }
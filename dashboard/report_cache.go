@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/go-github/v61/github"
+
+	"github.com/neurodesk/dashboard/internal/gh"
+	"github.com/neurodesk/dashboard/internal/reportcache"
+)
+
+// openReportCache opens the on-disk report cache under
+// reportcache.DefaultDir(). Opening it is a cheap MkdirAll, so callers are
+// expected to call this per use rather than hold a long-lived handle.
+// Failure to open (e.g. an unwritable home directory) is non-fatal: callers
+// fall back to always fetching live, same as attachFlakeHistory's handling
+// of a failed flake-history load.
+func openReportCache() reportcache.ReportStore {
+	dir := reportcache.DefaultDir()
+	store, err := reportcache.NewStore(dir, reportcache.DefaultTTL)
+	if err != nil {
+		slog.Warn("open report cache", "dir", dir, "error", err)
+		return nil
+	}
+	return store
+}
+
+// fetchIssueComments returns issue's comments, preferring the on-disk cache
+// when it's fresh and issue hasn't been updated since it was cached. This
+// is what lets multi-run history parsing (loadFlakeHistory) walk hundreds
+// of issues per page-load without re-paging through all of their comments
+// on every dashboard rebuild.
+//
+// Once the cache entry is stale (past TTL, or updated_at has moved),
+// revalidation uses the entry's ETag via gh.FetchIssueCommentsConditional
+// instead of an unconditional fetch, so a 304 from GitHub - the common case
+// once updated_at is the only thing that moved, e.g. a label change - costs
+// nothing against rate limit and the previously cached comments are kept.
+func fetchIssueComments(ctx context.Context, client *github.Client, owner, repo string, issue *github.Issue, refresh bool) ([]*github.IssueComment, error) {
+	cache := openReportCache()
+
+	var cached *reportcache.Entry
+	if cache != nil && !refresh {
+		if entry, ok := cache.Get(owner, repo, issue.GetNumber()); ok {
+			if entry.Fresh(cache.TTL()) && entry.UpdatedAt.Equal(issue.GetUpdatedAt().Time) {
+				return entry.Comments, nil
+			}
+			cached = entry
+		}
+	}
+
+	var etag string
+	if cached != nil {
+		etag = cached.ETag
+	}
+
+	comments, newETag, notModified, err := gh.FetchIssueCommentsConditional(ctx, client, owner, repo, issue.GetNumber(), etag)
+	if err != nil {
+		return nil, err
+	}
+	if notModified {
+		comments = cached.Comments
+	}
+
+	if cache != nil {
+		entry := &reportcache.Entry{
+			UpdatedAt: issue.GetUpdatedAt().Time,
+			CachedAt:  time.Now(),
+			ETag:      newETag,
+			Issue:     issue,
+			Comments:  comments,
+		}
+		if err := cache.Put(owner, repo, issue.GetNumber(), entry); err != nil {
+			slog.Warn("write report cache", "owner", owner, "repo", repo, "issue", issue.GetNumber(), "error", err)
+		}
+	}
+
+	return comments, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/neurodesk/dashboard/internal/triage"
+)
+
+// runReport fetches and parses the latest test run report and prints it in
+// the requested format, so downstream tools can consume it without
+// re-scraping the GitHub issue themselves.
+func runReport(args []string) error {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	format := fs.String("format", "json", "output format (only \"json\" is supported)")
+	triageScript := fs.String("triage-script", os.Getenv("DASHBOARD_TRIAGE_SCRIPT"), "path to a triage rules file classifying container test failures")
+	refresh := fs.Bool("refresh", false, "bypass the on-disk GitHub issue/comment cache")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *format != "json" {
+		return fmt.Errorf("report: unsupported --format %q", *format)
+	}
+
+	var triageEngine *triage.Engine
+	if *triageScript != "" {
+		engine, err := triage.ParseFile(*triageScript)
+		if err != nil {
+			return fmt.Errorf("parse triage script: %w", err)
+		}
+		triageEngine = engine
+	}
+
+	ctx := context.Background()
+	report, err := loadLatestTestRunReport(ctx, triageEngine, *refresh)
+	if err != nil {
+		return fmt.Errorf("load github test report: %w", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
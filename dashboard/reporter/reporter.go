@@ -0,0 +1,346 @@
+// Package reporter syncs dashboard warnings to GitHub, opening or updating
+// one tracking issue per container that has unresolved warnings.
+package reporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v61/github"
+	"golang.org/x/mod/semver"
+)
+
+// Container is the subset of dashboard state a Reporter needs to sync.
+type Container struct {
+	Name            string
+	Warnings        []string
+	BuildVersion    string
+	HasBuildYAML    bool
+	ReleaseVersions []string
+}
+
+// Reporter syncs the current set of container warnings to an external
+// system (GitHub issues, a log, etc).
+type Reporter interface {
+	Sync(ctx context.Context, containers []Container) error
+}
+
+// titlePrefix identifies issues this reporter owns, so syncing dedupes
+// against prior runs instead of opening duplicates.
+const titlePrefix = "[dashboard] "
+
+// DryRunReporter logs what it would do without making any GitHub API calls.
+// It is the default unless --apply is passed, so CI can produce a
+// diff-style summary safely.
+type DryRunReporter struct{}
+
+func (DryRunReporter) Sync(ctx context.Context, containers []Container) error {
+	for _, c := range containers {
+		if len(c.Warnings) == 0 {
+			continue
+		}
+		slog.Info("dry-run: would sync issue", "container", c.Name, "title", issueTitle(c.Name), "warnings", len(c.Warnings))
+	}
+	return nil
+}
+
+// GitHubReporter opens or updates one tracking issue per container with
+// unresolved warnings, deduping by titlePrefix rather than opening
+// duplicates, and optionally opens a draft PR seeding a release file when a
+// container's build.yaml is ahead of every release.
+type GitHubReporter struct {
+	Client *github.Client
+	Owner  string
+	Repo   string
+
+	// OpenDraftPRs enables opening a draft PR against releases/<container>/
+	// for containers whose build.yaml version is semver-greater than every
+	// released version (see buildVersionAheadOfReleases).
+	OpenDraftPRs bool
+}
+
+func (r *GitHubReporter) Sync(ctx context.Context, containers []Container) error {
+	existing, err := r.listOwnedIssues(ctx)
+	if err != nil {
+		return fmt.Errorf("list existing issues: %w", err)
+	}
+
+	for _, c := range containers {
+		if len(c.Warnings) == 0 {
+			continue
+		}
+		if err := r.syncContainer(ctx, c, existing); err != nil {
+			return fmt.Errorf("sync %s: %w", c.Name, err)
+		}
+		if r.OpenDraftPRs && buildVersionAheadOfReleases(c) {
+			if err := r.openDraftPR(ctx, c); err != nil {
+				return fmt.Errorf("open draft PR for %s: %w", c.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *GitHubReporter) syncContainer(ctx context.Context, c Container, existing map[string]*github.Issue) error {
+	title := issueTitle(c.Name)
+	body := issueBody(c)
+
+	if issue, ok := existing[title]; ok {
+		if issue.GetBody() == body {
+			return nil
+		}
+		return r.withBackoff(ctx, func() error {
+			_, _, err := r.Client.Issues.Edit(ctx, r.Owner, r.Repo, issue.GetNumber(), &github.IssueRequest{Body: &body})
+			return err
+		})
+	}
+
+	return r.withBackoff(ctx, func() error {
+		_, _, err := r.Client.Issues.Create(ctx, r.Owner, r.Repo, &github.IssueRequest{Title: &title, Body: &body})
+		return err
+	})
+}
+
+func (r *GitHubReporter) listOwnedIssues(ctx context.Context) (map[string]*github.Issue, error) {
+	opt := &github.IssueListByRepoOptions{
+		State:       "open",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	owned := make(map[string]*github.Issue)
+	for {
+		var issues []*github.Issue
+		var resp *github.Response
+		err := r.withBackoff(ctx, func() error {
+			var listErr error
+			issues, resp, listErr = r.Client.Issues.ListByRepo(ctx, r.Owner, r.Repo, opt)
+			return listErr
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if strings.HasPrefix(issue.GetTitle(), titlePrefix) {
+				owned[issue.GetTitle()] = issue
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return owned, nil
+}
+
+// withBackoff retries fn on GitHub rate limit errors, sleeping until the
+// rate limit resets rather than failing the whole sync.
+//
+// This overlaps with gh.retryTransport, which already waits out a primary
+// rate limit inside a single RoundTrip: that transport's retries are capped
+// (maxRetries) so one slow HTTP call can't block forever, and surfaces a
+// *github.RateLimitError once exhausted instead of swallowing it. This loop
+// is the layer that catches that error and keeps going, uncapped, since
+// reporting a sync failure over a rate limit that will simply reset later
+// is worse than waiting here. The duplication is intentional, not an
+// oversight: each layer bounds a different kind of retry.
+func (r *GitHubReporter) withBackoff(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		var rateLimitErr *github.RateLimitError
+		if errors.As(err, &rateLimitErr) {
+			wait := time.Until(rateLimitErr.Rate.Reset.Time)
+			if wait < 0 {
+				wait = time.Second
+			}
+			slog.Warn("rate limited, waiting", "wait", wait)
+			select {
+			case <-time.After(wait):
+				continue
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		var abuseErr *github.AbuseRateLimitError
+		if errors.As(err, &abuseErr) && attempt < 5 {
+			wait := time.Second * 5
+			if abuseErr.RetryAfter != nil {
+				wait = *abuseErr.RetryAfter
+			}
+			slog.Warn("secondary rate limited, waiting", "wait", wait)
+			select {
+			case <-time.After(wait):
+				continue
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		return err
+	}
+}
+
+// draftPRBranchPrefix identifies branches this reporter owns, mirroring
+// titlePrefix's role for issues.
+const draftPRBranchPrefix = "dashboard/release-"
+
+// buildVersionAheadOfReleases reports whether c's build.yaml version is
+// semver-greater than every released version, the condition under which
+// openDraftPR seeds a placeholder release.
+func buildVersionAheadOfReleases(c Container) bool {
+	if !c.HasBuildYAML || c.BuildVersion == "" {
+		return false
+	}
+	buildKey := canonicalSemver(c.BuildVersion)
+	if buildKey == "" {
+		return false
+	}
+	for _, rv := range c.ReleaseVersions {
+		releaseKey := canonicalSemver(rv)
+		if releaseKey == "" {
+			continue
+		}
+		if semver.Compare(buildKey, releaseKey) <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// canonicalSemver canonicalizes raw into a comparable semver.Canonical form,
+// returning "" if raw isn't semver-shaped.
+func canonicalSemver(raw string) string {
+	candidate := raw
+	if !strings.HasPrefix(candidate, "v") {
+		candidate = "v" + candidate
+	}
+	if !semver.IsValid(candidate) {
+		return ""
+	}
+	return semver.Canonical(candidate)
+}
+
+// openDraftPR opens a draft PR against releases/<container>/ seeding a
+// placeholder JSON derived from the build.yaml, for a container whose
+// build.yaml version is ahead of every release. It is a no-op if a PR for the
+// same branch is already open.
+func (r *GitHubReporter) openDraftPR(ctx context.Context, c Container) error {
+	branch := draftPRBranchPrefix + c.Name + "-" + c.BuildVersion
+
+	open, err := r.hasOpenPR(ctx, branch)
+	if err != nil {
+		return fmt.Errorf("check existing PRs: %w", err)
+	}
+	if open {
+		return nil
+	}
+
+	repo, _, err := r.Client.Repositories.Get(ctx, r.Owner, r.Repo)
+	if err != nil {
+		return fmt.Errorf("get repo: %w", err)
+	}
+	base := repo.GetDefaultBranch()
+
+	baseRef, _, err := r.Client.Git.GetRef(ctx, r.Owner, r.Repo, "refs/heads/"+base)
+	if err != nil {
+		return fmt.Errorf("get base ref: %w", err)
+	}
+
+	err = r.withBackoff(ctx, func() error {
+		_, _, err := r.Client.Git.CreateRef(ctx, r.Owner, r.Repo, &github.Reference{
+			Ref:    github.String("refs/heads/" + branch),
+			Object: &github.GitObject{SHA: baseRef.Object.SHA},
+		})
+		return err
+	})
+	if err != nil && !strings.Contains(err.Error(), "Reference already exists") {
+		return fmt.Errorf("create branch: %w", err)
+	}
+
+	path := fmt.Sprintf("releases/%s/%s.json", c.Name, c.BuildVersion)
+	commitMsg := fmt.Sprintf("Add placeholder release for %s %s", c.Name, c.BuildVersion)
+	err = r.withBackoff(ctx, func() error {
+		_, _, err := r.Client.Repositories.CreateFile(ctx, r.Owner, r.Repo, path, &github.RepositoryContentFileOptions{
+			Message: &commitMsg,
+			Content: placeholderReleaseJSON(c),
+			Branch:  &branch,
+		})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("seed placeholder release: %w", err)
+	}
+
+	title := fmt.Sprintf("%s: release %s", c.Name, c.BuildVersion)
+	body := fmt.Sprintf("Dashboard-detected: `%s`'s build.yaml is at version `%s`, ahead of every released version. This seeds a placeholder release file for review.", c.Name, c.BuildVersion)
+	return r.withBackoff(ctx, func() error {
+		_, _, err := r.Client.PullRequests.Create(ctx, r.Owner, r.Repo, &github.NewPullRequest{
+			Title: &title,
+			Head:  &branch,
+			Base:  &base,
+			Body:  &body,
+			Draft: github.Bool(true),
+		})
+		return err
+	})
+}
+
+// hasOpenPR reports whether an open PR already exists with the given head
+// branch, so repeated syncs don't try to recreate it.
+func (r *GitHubReporter) hasOpenPR(ctx context.Context, branch string) (bool, error) {
+	opt := &github.PullRequestListOptions{
+		State: "open",
+		Head:  fmt.Sprintf("%s:%s", r.Owner, branch),
+	}
+	var prs []*github.PullRequest
+	err := r.withBackoff(ctx, func() error {
+		var listErr error
+		prs, _, listErr = r.Client.PullRequests.List(ctx, r.Owner, r.Repo, opt)
+		return listErr
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(prs) > 0, nil
+}
+
+// placeholderReleaseJSON derives a minimal release JSON from a container's
+// build.yaml fields, for a maintainer to fill in and merge.
+func placeholderReleaseJSON(c Container) []byte {
+	placeholder := struct {
+		Container string `json:"container"`
+		Version   string `json:"version"`
+		Status    string `json:"status"`
+	}{
+		Container: c.Name,
+		Version:   c.BuildVersion,
+		Status:    "pending-review",
+	}
+	data, err := json.MarshalIndent(placeholder, "", "  ")
+	if err != nil {
+		return []byte("{}")
+	}
+	return data
+}
+
+func issueTitle(container string) string {
+	return fmt.Sprintf("%s%s: unresolved warnings", titlePrefix, container)
+}
+
+func issueBody(c Container) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Dashboard-detected warnings for `%s`:\n\n", c.Name)
+	for _, w := range c.Warnings {
+		fmt.Fprintf(&b, "- %s\n", w)
+	}
+	return b.String()
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// serveOptions configures the long-running HTTP serve mode.
+type serveOptions struct {
+	Addr           string
+	BuildOpts      BuildOptions
+	WebhookSecret  string
+	RebuildToken   string
+	DebounceWindow time.Duration
+}
+
+// builtDashboard is one rebuild's worth of servable state: the index page
+// data plus every container's rendered HTML page, keyed by container name -
+// the serve-mode equivalent of the containers/<name>.html files the static
+// renderDashboard path writes to disk.
+type builtDashboard struct {
+	data           indexData
+	containerPages map[string][]byte
+}
+
+// dashboardServer keeps the most recently built dashboard available for
+// lock-free HTTP reads, and serializes rebuilds triggered by webhooks or the
+// /rebuild endpoint behind a mutex plus a debounce timer.
+type dashboardServer struct {
+	opts serveOptions
+
+	current atomic.Value // holds builtDashboard
+
+	rebuildMu   sync.Mutex
+	debounceMu  sync.Mutex
+	debounceTmr *time.Timer
+}
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	releasesDir := fs.String("releases-dir", "", "path to container release metadata (defaults to releases/)")
+	recipesDir := fs.String("recipes-dir", "", "path to container recipes (defaults to recipes/)")
+	appsJSON := fs.String("apps-json", "", "path to apps.json for comparing published apps")
+	skipGitHub := fs.Bool("skip-github", false, "skip fetching the latest GitHub test run report")
+	debounce := fs.Duration("debounce", 10*time.Second, "coalesce webhook-triggered rebuilds within this window")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := serveOptions{
+		Addr: *addr,
+		BuildOpts: BuildOptions{
+			SkipGitHub: *skipGitHub,
+			DataSources: DataSources{
+				ReleasesDir:  *releasesDir,
+				RecipesDir:   *recipesDir,
+				AppsJSONPath: *appsJSON,
+			},
+		},
+		WebhookSecret:  os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		RebuildToken:   os.Getenv("DASHBOARD_REBUILD_TOKEN"),
+		DebounceWindow: *debounce,
+	}
+
+	srv := &dashboardServer{opts: opts}
+
+	ctx := context.Background()
+	if err := srv.rebuild(ctx); err != nil {
+		return fmt.Errorf("initial build: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", srv.handleIndex)
+	mux.HandleFunc("/containers/", srv.handleContainerPage)
+	mux.HandleFunc("/api/containers", srv.handleAPIContainers)
+	mux.HandleFunc("/api/summary", srv.handleAPISummary)
+	mux.HandleFunc("/api/groups", srv.handleAPIGroups)
+	mux.HandleFunc("/webhooks/github", srv.handleWebhook)
+	mux.HandleFunc("/healthz", srv.handleHealthz)
+	mux.HandleFunc("/rebuild", srv.handleRebuild)
+
+	slog.Info("dashboard serving", "addr", opts.Addr)
+	return http.ListenAndServe(opts.Addr, mux)
+}
+
+func (s *dashboardServer) data() (indexData, bool) {
+	v := s.current.Load()
+	if v == nil {
+		return indexData{}, false
+	}
+	return v.(builtDashboard).data, true
+}
+
+func (s *dashboardServer) containerPage(name string) ([]byte, bool) {
+	v := s.current.Load()
+	if v == nil {
+		return nil, false
+	}
+	page, ok := v.(builtDashboard).containerPages[name]
+	return page, ok
+}
+
+// rebuild runs buildDashboardData, renders every container's detail page the
+// same way the static renderDashboard path does, and swaps the result into
+// current. Concurrent rebuilds are serialized so two webhooks never race on
+// the underlying data sources.
+func (s *dashboardServer) rebuild(ctx context.Context) error {
+	s.rebuildMu.Lock()
+	defer s.rebuildMu.Unlock()
+
+	data, err := buildDashboardData(ctx, s.opts.BuildOpts)
+	if err != nil {
+		return err
+	}
+
+	if data.TestRun != nil {
+		syncTestRunHistories(ctx, data.TestRun)
+	}
+	var flakes *FlakeHistory
+	if data.TestRun != nil {
+		flakes = data.TestRun.FlakeHistory
+	}
+
+	containerPages := make(map[string][]byte, len(data.Containers))
+	for _, container := range data.Containers {
+		pageData, err := buildContainerPageData(container, flakes)
+		if err != nil {
+			slog.Warn("build container page", "container", container.Name, "error", err)
+			continue
+		}
+		var buf bytes.Buffer
+		if err := renderContainerPageHTML(&buf, pageData); err != nil {
+			slog.Warn("render container page", "container", container.Name, "error", err)
+			continue
+		}
+		containerPages[container.Name] = buf.Bytes()
+	}
+
+	s.current.Store(builtDashboard{data: data, containerPages: containerPages})
+	slog.Info("dashboard rebuilt", "containers", len(data.Containers))
+	return nil
+}
+
+// scheduleRebuild debounces rebuild requests: repeated calls within the
+// configured window collapse into a single rebuild once things go quiet.
+func (s *dashboardServer) scheduleRebuild() {
+	s.debounceMu.Lock()
+	defer s.debounceMu.Unlock()
+
+	if s.debounceTmr != nil {
+		s.debounceTmr.Stop()
+	}
+	s.debounceTmr = time.AfterFunc(s.opts.DebounceWindow, func() {
+		if err := s.rebuild(context.Background()); err != nil {
+			slog.Error("debounced rebuild", "error", err)
+		}
+	})
+}
+
+func (s *dashboardServer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	data, ok := s.data()
+	if !ok {
+		http.Error(w, "dashboard not yet built", http.StatusServiceUnavailable)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, "index.html", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// handleContainerPage serves the rendered containers/<name>.html page built
+// by the most recent rebuild, matching the path the static index.html links
+// to.
+func (s *dashboardServer) handleContainerPage(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/containers/"), ".html")
+	page, ok := s.containerPage(name)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(page)
+}
+
+func (s *dashboardServer) handleAPIContainers(w http.ResponseWriter, r *http.Request) {
+	data, ok := s.data()
+	if !ok {
+		http.Error(w, "dashboard not yet built", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, data.Containers)
+}
+
+func (s *dashboardServer) handleAPISummary(w http.ResponseWriter, r *http.Request) {
+	data, ok := s.data()
+	if !ok {
+		http.Error(w, "dashboard not yet built", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, data.Summary)
+}
+
+func (s *dashboardServer) handleAPIGroups(w http.ResponseWriter, r *http.Request) {
+	data, ok := s.data()
+	if !ok {
+		http.Error(w, "dashboard not yet built", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, data.ContainerGroups)
+}
+
+func (s *dashboardServer) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (s *dashboardServer) handleRebuild(w http.ResponseWriter, r *http.Request) {
+	if s.opts.RebuildToken != "" {
+		if r.URL.Query().Get("token") != s.opts.RebuildToken {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+	}
+	if err := s.rebuild(r.Context()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("rebuilt"))
+}
+
+// githubWebhookEvent is the subset of fields we need across push, release,
+// and workflow_run payloads to decide whether a rebuild is warranted.
+type githubWebhookEvent struct {
+	Action      string `json:"action"`
+	WorkflowRun *struct {
+		Conclusion string `json:"conclusion"`
+	} `json:"workflow_run"`
+	Commits []struct {
+		Modified []string `json:"modified"`
+		Added    []string `json:"added"`
+		Removed  []string `json:"removed"`
+	} `json:"commits"`
+	Release *struct {
+		TagName string `json:"tag_name"`
+	} `json:"release"`
+}
+
+func (s *dashboardServer) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 10<<20))
+	if err != nil {
+		http.Error(w, "read body", http.StatusBadRequest)
+		return
+	}
+
+	if s.opts.WebhookSecret != "" {
+		if err := verifyGitHubSignature(s.opts.WebhookSecret, r.Header.Get("X-Hub-Signature-256"), body); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	event := r.Header.Get("X-GitHub-Event")
+	var payload githubWebhookEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if relevantWebhookEvent(event, payload) {
+		s.scheduleRebuild()
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = w.Write([]byte("accepted"))
+}
+
+// relevantWebhookEvent decides whether a webhook payload indicates a change
+// to releases/, recipes/, or apps.json that warrants a rebuild.
+func relevantWebhookEvent(event string, payload githubWebhookEvent) bool {
+	switch event {
+	case "release":
+		return payload.Release != nil
+	case "workflow_run":
+		return payload.WorkflowRun != nil && payload.WorkflowRun.Conclusion == "success"
+	case "push":
+		for _, commit := range payload.Commits {
+			for _, path := range concatStringSlices(commit.Added, commit.Modified, commit.Removed) {
+				if pathTriggersRebuild(path) {
+					return true
+				}
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func concatStringSlices(slices ...[]string) []string {
+	var all []string
+	for _, s := range slices {
+		all = append(all, s...)
+	}
+	return all
+}
+
+func pathTriggersRebuild(path string) bool {
+	for _, prefix := range []string{"releases/", "recipes/", "apps.json"} {
+		if hasPrefixOrEqual(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPrefixOrEqual(path, prefix string) bool {
+	if path == prefix {
+		return true
+	}
+	return len(path) > len(prefix) && path[:len(prefix)] == prefix
+}
+
+func verifyGitHubSignature(secret, header string, body []byte) error {
+	if header == "" {
+		return errors.New("missing X-Hub-Signature-256 header")
+	}
+	const sigPrefix = "sha256="
+	if len(header) <= len(sigPrefix) || header[:len(sigPrefix)] != sigPrefix {
+		return errors.New("malformed signature header")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	got, err := hex.DecodeString(header[len(sigPrefix):])
+	if err != nil {
+		return errors.New("malformed signature encoding")
+	}
+
+	if subtle.ConstantTimeCompare(expected, got) != 1 {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		slog.Error("encode json response", "error", err)
+	}
+}
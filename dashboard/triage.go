@@ -0,0 +1,41 @@
+package main
+
+import (
+	"github.com/neurodesk/dashboard/internal/triage"
+)
+
+// applyTriage classifies and tags every entry (and, where present, every
+// per-test breakdown within it) in place using engine. A nil engine is a
+// no-op so callers don't need to special-case -triage-script being unset.
+func applyTriage(engine *triage.Engine, entries map[string]TestRunEntry) {
+	if engine == nil {
+		return
+	}
+
+	for container, entry := range entries {
+		if len(entry.Tests) > 0 {
+			for i := range entry.Tests {
+				t := &entry.Tests[i]
+				v := engine.Evaluate(triage.Fields{
+					Container:  container,
+					TestName:   t.Name,
+					Status:     string(t.Status),
+					Stdout:     t.Stdout,
+					Stderr:     t.Stderr,
+					ReturnCode: t.ReturnCode,
+				})
+				t.Classification = v.Classification
+				t.Tags = v.Tags
+			}
+		} else {
+			v := engine.Evaluate(triage.Fields{
+				Container: container,
+				Status:    string(entry.Status),
+				Stderr:    entry.Details,
+			})
+			entry.Classification = v.Classification
+			entry.Tags = v.Tags
+		}
+		entries[container] = entry
+	}
+}
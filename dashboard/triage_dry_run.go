@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/neurodesk/dashboard/internal/triage"
+)
+
+// runTriageDryRun parses a triage rules file, fetches the latest GitHub test
+// run report, and prints which rule (if any) matched each failing entry, or
+// each non-passing test within it when it has a per-test breakdown. It's meant for iterating on rules against a real report without
+// regenerating the whole dashboard.
+func runTriageDryRun(args []string) error {
+	fs := flag.NewFlagSet("triage-dry-run", flag.ExitOnError)
+	triageScript := fs.String("triage-script", os.Getenv("DASHBOARD_TRIAGE_SCRIPT"), "path to a triage rules file classifying container test failures")
+	refresh := fs.Bool("refresh", false, "bypass the on-disk GitHub issue/comment cache")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *triageScript == "" {
+		return fmt.Errorf("triage-dry-run: --triage-script (or DASHBOARD_TRIAGE_SCRIPT) is required")
+	}
+
+	engine, err := triage.ParseFile(*triageScript)
+	if err != nil {
+		return fmt.Errorf("parse triage script: %w", err)
+	}
+
+	ctx := context.Background()
+	report, err := loadLatestTestRunReport(ctx, nil, *refresh)
+	if err != nil {
+		return fmt.Errorf("load github test report: %w", err)
+	}
+	if report == nil {
+		fmt.Println("no container test run issue found")
+		return nil
+	}
+
+	for _, entry := range report.Failures {
+		if len(entry.Tests) == 0 {
+			printTriageMatch(engine, entry.Container, "", triage.Fields{
+				Container: entry.Container,
+				Status:    string(entry.Status),
+				Stderr:    entry.Details,
+			})
+			continue
+		}
+		for _, t := range entry.Tests {
+			if t.Status == TestRunStatusPassed {
+				continue
+			}
+			printTriageMatch(engine, entry.Container, t.Name, triage.Fields{
+				Container:  entry.Container,
+				TestName:   t.Name,
+				Status:     string(t.Status),
+				Stdout:     t.Stdout,
+				Stderr:     t.Stderr,
+				ReturnCode: t.ReturnCode,
+			})
+		}
+	}
+
+	return nil
+}
+
+func printTriageMatch(engine *triage.Engine, container, testName string, fields triage.Fields) {
+	label := container
+	if testName != "" {
+		label = container + "/" + testName
+	}
+
+	v := engine.Evaluate(fields)
+	if len(v.MatchedRules) == 0 {
+		fmt.Printf("%s: no rule matched\n", label)
+		return
+	}
+
+	fmt.Printf("%s: classification=%q tags=%v\n", label, v.Classification, v.Tags)
+	for _, r := range v.MatchedRules {
+		fmt.Printf("  matched line %d: %s\n", r.Line, r.Source)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v61/github"
+
+	"github.com/neurodesk/dashboard/internal/gh"
+	"github.com/neurodesk/dashboard/internal/triage"
+)
+
+// triageCommentMarker hides inside the posted comment's body so
+// postTriageSummary can find and edit its own prior comment rather than
+// appending a new one on every run.
+const triageCommentMarker = "<!-- neurocontainers-triage:v1 -->"
+
+// postTriageSummary loads the latest test run report, classifies it with
+// opts.TriageScript (if set), and writes or updates a single "Triage
+// summary" comment on that report's issue.
+func postTriageSummary(ctx context.Context, opts BuildOptions) error {
+	var triageEngine *triage.Engine
+	if opts.TriageScript != "" {
+		engine, err := triage.ParseFile(opts.TriageScript)
+		if err != nil {
+			return fmt.Errorf("parse triage script: %w", err)
+		}
+		triageEngine = engine
+	}
+
+	report, err := loadLatestTestRunReport(ctx, triageEngine, opts.RefreshCache)
+	if err != nil {
+		return fmt.Errorf("load github test report: %w", err)
+	}
+	if report == nil {
+		return fmt.Errorf("no container test run issue found")
+	}
+
+	client, err := newGitHubClient(ctx)
+	if err != nil {
+		return err
+	}
+	owner, repo := gitHubRepo()
+
+	body := triageSummaryBody(report)
+
+	existing, err := findTriageComment(ctx, client, owner, repo, report.IssueNumber)
+	if err != nil {
+		return fmt.Errorf("find existing triage comment: %w", err)
+	}
+
+	if existing != nil {
+		_, _, err := client.Issues.EditComment(ctx, owner, repo, existing.GetID(), &github.IssueComment{Body: &body})
+		return err
+	}
+
+	_, _, err = client.Issues.CreateComment(ctx, owner, repo, report.IssueNumber, &github.IssueComment{Body: &body})
+	return err
+}
+
+// findTriageComment looks for a prior comment on the issue carrying
+// triageCommentMarker, so postTriageSummary can edit it in place.
+func findTriageComment(ctx context.Context, client *github.Client, owner, repo string, issueNumber int) (*github.IssueComment, error) {
+	comments, err := gh.ListAllIssueComments(ctx, client, owner, repo, issueNumber)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range comments {
+		if strings.Contains(c.GetBody(), triageCommentMarker) {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+// triageSummaryBody renders the "Triage summary" comment body: counts of
+// flaky vs genuine failures drawn from FlakeHistory, a link to the most
+// recent failing run for each flaky test, and a collapsed list of skipped
+// containers with their reasons.
+func triageSummaryBody(report *TestRunReport) string {
+	var b strings.Builder
+
+	fmt.Fprintln(&b, triageCommentMarker)
+	fmt.Fprintln(&b, "## Triage summary")
+	fmt.Fprintln(&b)
+
+	flaky, genuine, flakyLinks := classifyFailures(report)
+
+	fmt.Fprintf(&b, "- Flaky failures: %d\n", flaky)
+	fmt.Fprintf(&b, "- Genuine failures: %d\n", genuine)
+	fmt.Fprintln(&b)
+
+	if len(flakyLinks) > 0 {
+		fmt.Fprintln(&b, "### Flaky tests")
+		fmt.Fprintln(&b)
+		for _, l := range flakyLinks {
+			fmt.Fprintln(&b, l)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if len(report.Skipped) > 0 {
+		fmt.Fprintln(&b, "<details>")
+		fmt.Fprintf(&b, "<summary>Skipped containers (%d)</summary>\n", len(report.Skipped))
+		fmt.Fprintln(&b)
+		for _, entry := range report.Skipped {
+			reason := strings.TrimSpace(entry.Details)
+			if reason == "" {
+				reason = "no reason recorded"
+			}
+			fmt.Fprintf(&b, "- `%s`: %s\n", entry.Container, reason)
+		}
+		fmt.Fprintln(&b, "</details>")
+	}
+
+	return b.String()
+}
+
+// classifyFailures counts failing test instances as flaky or genuine using
+// report.FlakeHistory, and collects a markdown link to the most recent
+// failing run for each flaky container+test pair.
+func classifyFailures(report *TestRunReport) (flaky, genuine int, flakyLinks []string) {
+	for _, entry := range report.Failures {
+		if len(entry.Tests) == 0 {
+			if isFlakyHistory(report, entry.Container, "") {
+				flaky++
+				flakyLinks = append(flakyLinks, flakyLink(report, entry.Container, ""))
+			} else {
+				genuine++
+			}
+			continue
+		}
+		for _, t := range entry.Tests {
+			if t.Status != TestRunStatusFailed {
+				continue
+			}
+			if isFlakyHistory(report, entry.Container, t.Name) {
+				flaky++
+				flakyLinks = append(flakyLinks, flakyLink(report, entry.Container, t.Name))
+			} else {
+				genuine++
+			}
+		}
+	}
+
+	sort.Strings(flakyLinks)
+	return flaky, genuine, flakyLinks
+}
+
+func isFlakyHistory(report *TestRunReport, container, test string) bool {
+	if report.FlakeHistory == nil {
+		return false
+	}
+	entry, ok := report.FlakeHistory.Entries[container][test]
+	return ok && entry.Classification == FlakeClassificationFlaky
+}
+
+func flakyLink(report *TestRunReport, container, test string) string {
+	label := container
+	if test != "" {
+		label = container + "/" + test
+	}
+
+	entry, ok := report.FlakeHistory.Entries[container][test]
+	if !ok || entry.LastFailureIssueURL == "" {
+		return fmt.Sprintf("- `%s`", label)
+	}
+	return fmt.Sprintf("- `%s`: [#%d](%s)", label, entry.LastFailureIssueNumber, entry.LastFailureIssueURL)
+}